@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status code ranges are a class (2xx) or an inclusive range (200-299)
+var statusCodeClassRegex = regexp.MustCompile(`^([1-5])xx$`)
+var statusCodeRangeRegex = regexp.MustCompile(`^([1-5][0-9]{2})-([1-5][0-9]{2})$`)
+
+// A JSONPath subset: the root followed by .name, ['name'] and [index] segments
+var jsonPathSegmentRegex = regexp.MustCompile(`^(?:\.([A-Za-z_$][A-Za-z0-9_$-]*)|\['([^']*)'\]|\[([0-9]+)\])`)
+
+// SyntheticsResponse is the response of a request, as checked against its expected response
+type SyntheticsResponse struct {
+	StatusCode int
+	Headers    http.Header
+	Payload    string
+	Latency    time.Duration
+	// Not after date of the leaf certificate, nil without TLS
+	CertificateNotAfter *time.Time
+	Now                 time.Time
+}
+
+// ValidateResponseModel checks the regexes, durations, code ranges and JSON paths of the expected response
+func ValidateResponseModel(expected ResponseModel) error {
+	for _, codeRange := range expected.CodeRanges {
+		if _, _, err := StatusCodeRangeBounds(codeRange); err != nil {
+			return fmt.Errorf("expectedResponse.codeRanges: %w", err)
+		}
+	}
+	for i, header := range expected.Headers {
+		if header.Name == "" {
+			return fmt.Errorf("expectedResponse.headers[%d].name is required", i)
+		}
+		if header.Value != "" && header.Matches != "" {
+			return fmt.Errorf("expectedResponse.headers[%d]: value and matches are exclusive", i)
+		}
+		if _, err := regexp.Compile(header.Matches); err != nil {
+			return fmt.Errorf("expectedResponse.headers[%d].matches: %w", i, err)
+		}
+	}
+	if _, err := regexp.Compile(expected.PayloadMatches); err != nil {
+		return fmt.Errorf("expectedResponse.payloadMatches: %w", err)
+	}
+	for i, assertion := range expected.JsonPath {
+		if _, err := ParseJsonPath(assertion.Path); err != nil {
+			return fmt.Errorf("expectedResponse.jsonPath[%d].path: %w", i, err)
+		}
+	}
+	if expected.MaxLatency != "" {
+		if latency, err := time.ParseDuration(expected.MaxLatency); err != nil || latency <= 0 {
+			return fmt.Errorf("expectedResponse.maxLatency: expected a positive duration like 500ms, got %q", expected.MaxLatency)
+		}
+	}
+	if expected.MinCertificateValidity != "" {
+		if _, err := ParseOpenSloDuration(expected.MinCertificateValidity); err != nil {
+			return fmt.Errorf("expectedResponse.minCertificateValidity: %w", err)
+		}
+	}
+	return nil
+}
+
+// CheckResponse returns the failed rules of the expected response. The status code must be one of the codes or in one
+// of the code ranges, or a non error status when none is expected. The expected response must be valid.
+func CheckResponse(expected ResponseModel, response SyntheticsResponse) []string {
+	var failures []string
+	if len(expected.Codes) > 0 || len(expected.CodeRanges) > 0 {
+		if !isExpectedStatusCode(expected, response.StatusCode) {
+			failures = append(failures, fmt.Sprintf("status code %d, expected one of %s", response.StatusCode, strings.Join(expectedStatusCodes(expected), ", ")))
+		}
+	} else if response.StatusCode >= 400 {
+		failures = append(failures, fmt.Sprintf("status code %d, expected a non error status", response.StatusCode))
+	}
+
+	for _, header := range expected.Headers {
+		values, ok := response.Headers[http.CanonicalHeaderKey(header.Name)]
+		value := strings.Join(values, ", ")
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("header %s is missing", header.Name))
+		case header.Value != "" && value != header.Value:
+			failures = append(failures, fmt.Sprintf("header %s is %q, expected %q", header.Name, value, header.Value))
+		case header.Matches != "" && !regexp.MustCompile(header.Matches).MatchString(value):
+			failures = append(failures, fmt.Sprintf("header %s is %q, expected to match %q", header.Name, value, header.Matches))
+		}
+	}
+
+	if expected.PayloadContains != "" && !strings.Contains(response.Payload, expected.PayloadContains) {
+		failures = append(failures, fmt.Sprintf("payload does not contain %q", expected.PayloadContains))
+	}
+	if expected.PayloadNotContains != "" && strings.Contains(response.Payload, expected.PayloadNotContains) {
+		failures = append(failures, fmt.Sprintf("payload contains %q", expected.PayloadNotContains))
+	}
+	if expected.PayloadMatches != "" && !regexp.MustCompile(expected.PayloadMatches).MatchString(response.Payload) {
+		failures = append(failures, fmt.Sprintf("payload does not match %q", expected.PayloadMatches))
+	}
+
+	if len(expected.JsonPath) > 0 {
+		var document interface{}
+		if err := json.Unmarshal([]byte(response.Payload), &document); err != nil {
+			failures = append(failures, fmt.Sprintf("payload is not JSON: %s", err.Error()))
+		} else {
+			failures = append(failures, checkJsonPath(expected.JsonPath, document)...)
+		}
+	}
+
+	if expected.MaxLatency != "" {
+		maxLatency, _ := time.ParseDuration(expected.MaxLatency)
+		if response.Latency > maxLatency {
+			failures = append(failures, fmt.Sprintf("latency %s, expected at most %s", response.Latency.Round(time.Millisecond), expected.MaxLatency))
+		}
+	}
+	if expected.MinCertificateValidity != "" {
+		minValidity, _ := ParseOpenSloDuration(expected.MinCertificateValidity)
+		if response.CertificateNotAfter == nil {
+			failures = append(failures, "no TLS certificate")
+		} else if response.CertificateNotAfter.Sub(response.Now) < minValidity {
+			failures = append(failures, fmt.Sprintf("TLS certificate expires on %s, expected to be valid for %s", response.CertificateNotAfter.Format(time.RFC3339), expected.MinCertificateValidity))
+		}
+	}
+	return failures
+}
+
+// StatusCodeRangeBounds returns the inclusive bounds of a status code class (2xx) or range (200-299)
+func StatusCodeRangeBounds(codeRange string) (int, int, error) {
+	if match := statusCodeClassRegex.FindStringSubmatch(codeRange); match != nil {
+		class, _ := strconv.Atoi(match[1])
+		return class * 100, class*100 + 99, nil
+	}
+	if match := statusCodeRangeRegex.FindStringSubmatch(codeRange); match != nil {
+		low, _ := strconv.Atoi(match[1])
+		high, _ := strconv.Atoi(match[2])
+		if low <= high {
+			return low, high, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("bad status code range %q, expected a class like 2xx or a range like 200-299", codeRange)
+}
+
+func isExpectedStatusCode(expected ResponseModel, statusCode int) bool {
+	for _, code := range expected.Codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	for _, codeRange := range expected.CodeRanges {
+		low, high, err := StatusCodeRangeBounds(codeRange)
+		if err == nil && statusCode >= low && statusCode <= high {
+			return true
+		}
+	}
+	return false
+}
+
+func expectedStatusCodes(expected ResponseModel) []string {
+	codes := []string{}
+	for _, code := range expected.Codes {
+		codes = append(codes, strconv.Itoa(code))
+	}
+	return append(codes, expected.CodeRanges...)
+}
+
+func checkJsonPath(assertions []JsonPathAssertionModel, document interface{}) []string {
+	var failures []string
+	for _, assertion := range assertions {
+		segments, _ := ParseJsonPath(assertion.Path)
+		value, ok := JsonPathLookup(document, segments)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s is missing", assertion.Path))
+			continue
+		}
+		if assertion.Value == "" {
+			continue
+		}
+		if actual := jsonPathValueString(value); actual != assertion.Value {
+			failures = append(failures, fmt.Sprintf("%s is %s, expected %s", assertion.Path, actual, assertion.Value))
+		}
+	}
+	return failures
+}
+
+// ParseJsonPath parses a path like $.items[0].name or $['content-type'] into object keys (string) and array indexes (int)
+func ParseJsonPath(path string) ([]interface{}, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("bad JSON path %q, expected to start with $", path)
+	}
+	var segments []interface{}
+	rest := path[1:]
+	for rest != "" {
+		match := jsonPathSegmentRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("bad JSON path %q at %q, expected .name, ['name'] or [index]", path, rest)
+		}
+		switch {
+		case match[1] != "":
+			segments = append(segments, match[1])
+		case match[3] != "":
+			index, _ := strconv.Atoi(match[3])
+			segments = append(segments, index)
+		default:
+			segments = append(segments, match[2])
+		}
+		rest = rest[len(match[0]):]
+	}
+	return segments, nil
+}
+
+// JsonPathLookup returns the value at the path segments of a decoded JSON document
+func JsonPathLookup(document interface{}, segments []interface{}) (interface{}, bool) {
+	value := document
+	for _, segment := range segments {
+		switch key := segment.(type) {
+		case string:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object[key]; !ok {
+				return nil, false
+			}
+		case int:
+			array, ok := value.([]interface{})
+			if !ok || key >= len(array) {
+				return nil, false
+			}
+			value = array[key]
+		}
+	}
+	return value, true
+}
+
+// jsonPathValueString returns strings as is, and other values as JSON
+func jsonPathValueString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
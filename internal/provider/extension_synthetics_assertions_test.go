@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const syntheticsAssertionsYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: orders-api
+spec:
+  url: https://orders.example.com
+  requests:
+  - name: list
+    path: /orders
+    expectedResponse:
+      code:
+      - 304
+      codeRanges:
+      - 2xx
+      headers:
+      - name: Content-Type
+        matches: ^application/json
+      - name: X-Request-Id
+      payloadMatches: '"orders":\s*\['
+      jsonPath:
+      - path: $.orders[0].id
+        value: "42"
+      - path: $.next
+      maxLatency: 500ms
+      minCertificateValidity: 14d
+`
+
+func TestSyntheticsAssertions_shouldbeValid_yamlSpec(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(syntheticsAssertionsYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(openslo.Extension_httpmonitor["orders-api"].Requests[0].ExpectedResponse, ResponseModel{
+		Codes:      []int{304},
+		CodeRanges: []string{"2xx"},
+		Headers: []HeaderAssertionModel{
+			{Name: "Content-Type", Matches: "^application/json"},
+			{Name: "X-Request-Id"},
+		},
+		PayloadMatches: `"orders":\s*\[`,
+		JsonPath: []JsonPathAssertionModel{
+			{Path: "$.orders[0].id", Value: "42"},
+			{Path: "$.next"},
+		},
+		MaxLatency:             "500ms",
+		MinCertificateValidity: "14d",
+	}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and state can be serialized, with the codes list
+	openSloState(t, &openslo)
+}
+
+func TestSyntheticsAssertions_shouldbeError_invalidAssertions(t *testing.T) {
+	cases := []struct {
+		response ResponseModel
+		expected string
+	}{
+		{ResponseModel{CodeRanges: []string{"2XX"}}, "expectedResponse.codeRanges"},
+		{ResponseModel{CodeRanges: []string{"299-200"}}, "expectedResponse.codeRanges"},
+		{ResponseModel{Headers: []HeaderAssertionModel{{Value: "x"}}}, "expectedResponse.headers[0].name"},
+		{ResponseModel{Headers: []HeaderAssertionModel{{Name: "a", Matches: "("}}}, "expectedResponse.headers[0].matches"},
+		{ResponseModel{PayloadMatches: "["}, "expectedResponse.payloadMatches"},
+		{ResponseModel{JsonPath: []JsonPathAssertionModel{{Path: "orders.id"}}}, "expectedResponse.jsonPath[0].path"},
+		{ResponseModel{JsonPath: []JsonPathAssertionModel{{Path: "$.orders[a]"}}}, "expectedResponse.jsonPath[0].path"},
+		{ResponseModel{MaxLatency: "1d"}, "expectedResponse.maxLatency"},
+		{ResponseModel{MinCertificateValidity: "two weeks"}, "expectedResponse.minCertificateValidity"},
+	}
+
+	for _, c := range cases {
+		// when
+		err := ValidateResponseModel(c.response)
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error naming %s, got %v", c.expected, err)
+		}
+	}
+
+	// and the monitor is rejected on extraction
+	err := (&OpenSloDataSource{}).GetOpenSloData(strings.Replace(syntheticsAssertionsYamlSpec, "2xx", "2XX", 1), &diag.Diagnostics{})
+	if err == nil || !strings.Contains(err.Error(), "http monitor orders-api requests[0]: expectedResponse.codeRanges") {
+		t.Errorf("Expected an extraction error, got %v", err)
+	}
+}
+
+func TestSyntheticsAssertions_shouldCheck_response(t *testing.T) {
+	// given
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresSoon := now.Add(7 * DAY)
+	expected := ResponseModel{
+		CodeRanges: []string{"200-204"},
+		Headers: []HeaderAssertionModel{
+			{Name: "content-type", Matches: "^application/json"},
+			{Name: "X-Version", Value: "2"},
+			{Name: "X-Request-Id"},
+		},
+		PayloadMatches: `"orders":\s*\[`,
+		JsonPath: []JsonPathAssertionModel{
+			{Path: "$.orders[0].id", Value: "42"},
+			{Path: "$['orders'][0]['state']", Value: "paid"},
+			{Path: "$.next"},
+		},
+		MaxLatency:             "500ms",
+		MinCertificateValidity: "14d",
+	}
+	cases := []struct {
+		name     string
+		response SyntheticsResponse
+		expected []string
+	}{
+		{
+			name: "passing",
+			response: SyntheticsResponse{
+				StatusCode:          204,
+				Headers:             http.Header{"Content-Type": {"application/json; charset=utf-8"}, "X-Version": {"2"}, "X-Request-Id": {"abc"}},
+				Payload:             `{"orders": [{"id": 42, "state": "paid"}], "next": null}`,
+				Latency:             100 * time.Millisecond,
+				CertificateNotAfter: &[]time.Time{now.Add(30 * DAY)}[0],
+				Now:                 now,
+			},
+		},
+		{
+			name: "failing",
+			response: SyntheticsResponse{
+				StatusCode:          500,
+				Headers:             http.Header{"Content-Type": {"text/plain"}, "X-Version": {"1"}},
+				Payload:             `{"orders": {}}`,
+				Latency:             time.Second,
+				CertificateNotAfter: &expiresSoon,
+				Now:                 now,
+			},
+			expected: []string{
+				"status code 500, expected one of 200-204",
+				`header content-type is "text/plain", expected to match "^application/json"`,
+				`header X-Version is "1", expected "2"`,
+				"header X-Request-Id is missing",
+				`payload does not match "\"orders\":\\s*\\["`,
+				"$.orders[0].id is missing",
+				"$['orders'][0]['state'] is missing",
+				"$.next is missing",
+				"latency 1s, expected at most 500ms",
+				"TLS certificate expires on 2024-01-08T00:00:00Z, expected to be valid for 14d",
+			},
+		},
+		{
+			name:     "without TLS",
+			response: SyntheticsResponse{StatusCode: 200, Payload: `{"orders": [{"id": "42", "state": "due"}]}`, Now: now},
+			expected: []string{
+				"header content-type is missing",
+				"header X-Version is missing",
+				"header X-Request-Id is missing",
+				"$['orders'][0]['state'] is due, expected paid",
+				"$.next is missing",
+				"no TLS certificate",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		// when
+		failures := CheckResponse(expected, c.response)
+
+		// then
+		if diff := deep.Equal(failures, c.expected); diff != nil {
+			t.Errorf("%s: %v", c.name, diff)
+		}
+	}
+}
+
+func TestSyntheticsAssertions_shouldCheck_tlsCertificate(t *testing.T) {
+	// given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+	request := RequestModel{ExpectedResponse: ResponseModel{
+		CodeRanges:             []string{"2xx"},
+		Headers:                []HeaderAssertionModel{{Name: "Content-Type", Value: "application/json"}},
+		JsonPath:               []JsonPathAssertionModel{{Path: "$.status", Value: "ok"}},
+		MinCertificateValidity: "30d",
+	}}
+
+	// when
+	checker := SyntheticsChecker{Client: server.Client()}
+	result := checker.CheckRequest(context.Background(), "monitor", server.URL, 0, request)
+
+	// then
+	if !result.Passed {
+		t.Errorf("Expected the check to pass, got %v", result.Failures)
+	}
+}
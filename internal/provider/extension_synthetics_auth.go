@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// validateAuth normalizes the auth type and checks the credentials it requires, keeping their secret references
+func validateAuth(auth AuthModel) (AuthModel, error) {
+	auth.Type = AuthType(strings.ToLower(string(auth.Type)))
+	required, err := authCredentials(&auth)
+	if err != nil {
+		return auth, err
+	}
+	for _, field := range sortedKeys(required) {
+		if *required[field] == "" {
+			return auth, fmt.Errorf("%s is required for %s auth", field, auth.Type)
+		}
+	}
+	return auth, nil
+}
+
+// resolveAuth checks the credentials required by the auth type and resolves their secret references
+func resolveAuth(auth AuthModel) (AuthModel, error) {
+	auth, err := validateAuth(auth)
+	if err != nil {
+		return auth, err
+	}
+	required, _ := authCredentials(&auth)
+	for _, field := range sortedKeys(required) {
+		resolved, err := resolveSecretReference(*required[field])
+		if err != nil {
+			return auth, fmt.Errorf("%s: %w", field, err)
+		}
+		*required[field] = resolved
+	}
+	return auth, nil
+}
+
+// authCredentials returns the credentials required by the auth type, by field name
+func authCredentials(auth *AuthModel) (map[string]*string, error) {
+	switch auth.Type {
+	case "":
+		return nil, nil
+	case AUTH_BASIC:
+		return map[string]*string{"username": &auth.Username, "password": &auth.Password}, nil
+	case AUTH_BEARER:
+		return map[string]*string{"token": &auth.Token}, nil
+	case AUTH_OAUTH2:
+		return map[string]*string{"tokenUrl": &auth.TokenUrl, "clientId": &auth.ClientId, "clientSecret": &auth.ClientSecret}, nil
+	}
+	return nil, fmt.Errorf("type %s is not supported, expected one of %v", auth.Type, []AuthType{AUTH_BASIC, AUTH_BEARER, AUTH_OAUTH2})
+}
+
+// secretReference splits an env:VAR or file:/path secret reference, ok is false for plain values
+func secretReference(value string) (prefix string, target string, ok bool) {
+	for _, prefix := range []string{SECRET_REF_ENV, SECRET_REF_FILE} {
+		if strings.HasPrefix(value, prefix) {
+			return prefix, strings.TrimPrefix(value, prefix), true
+		}
+	}
+	return "", value, false
+}
+
+// authenticate sets the Authorization header of the request. OAuth2 tokens are requested with the client
+// credentials grant, the client authenticating with basic auth.
+func (c *SyntheticsChecker) authenticate(ctx context.Context, request *http.Request, auth AuthModel) error {
+	switch auth.Type {
+	case AUTH_BASIC:
+		request.SetBasicAuth(auth.Username, auth.Password)
+	case AUTH_BEARER:
+		request.Header.Set("Authorization", "Bearer "+auth.Token)
+	case AUTH_OAUTH2:
+		token, err := c.clientCredentialsToken(ctx, auth)
+		if err != nil {
+			return fmt.Errorf("oauth2 token: %w", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func (c *SyntheticsChecker) clientCredentialsToken(ctx context.Context, auth AuthModel) (string, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(auth.Scopes, " "))
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.TokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(auth.ClientId), url.QueryEscape(auth.ClientSecret))
+
+	response, err := c.Client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	payload, err := io.ReadAll(io.LimitReader(response.Body, SYNTHETICS_CHECK_MAX_BODY_BYTES))
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status code %d", response.StatusCode)
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint returned no access_token")
+	}
+	return token.AccessToken, nil
+}
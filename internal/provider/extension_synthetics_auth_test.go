@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+const syntheticsAuthYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: orders-api
+spec:
+  url: https://orders.example.com
+  requests:
+  - name: basic
+    path: /orders
+    auth:
+      type: basic
+      username: synthetic
+      password: env:TEST_SYNTHETICS_PASSWORD
+  - name: bearer
+    path: /orders
+    auth:
+      type: Bearer
+      token: file:%s
+  - name: anonymous
+    path: /health
+`
+
+func TestSyntheticsAuth_shouldResolve_secretReferences(t *testing.T) {
+	// given
+	t.Setenv("TEST_SYNTHETICS_PASSWORD", "s3cr3t")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("t0k3n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsAuthYamlSpec, "%s", tokenFile, 1), &diag.Diagnostics{})
+
+	// then the openslo data source keeps the references
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := openslo.Extension_httpmonitor["orders-api"].Requests
+	if diff := deep.Equal([]AuthModel{requests[0].Auth, requests[1].Auth, requests[2].Auth}, []AuthModel{
+		{Type: AUTH_BASIC, Username: "synthetic", Password: "env:TEST_SYNTHETICS_PASSWORD"},
+		{Type: AUTH_BEARER, Token: "file:" + tokenFile},
+		{},
+	}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the synthetics check resolves them
+	resolved := OpenSloDataSource{}
+	if err := resolved.GetOpenSloData(strings.Replace(syntheticsAuthYamlSpec, "%s", tokenFile, 1), &diag.Diagnostics{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolved.ResolveAuthSecretReferences(); err != nil {
+		t.Fatal(err)
+	}
+	requests = resolved.Extension_httpmonitor["orders-api"].Requests
+	if diff := deep.Equal([]AuthModel{requests[0].Auth, requests[1].Auth, requests[2].Auth}, []AuthModel{
+		{Type: AUTH_BASIC, Username: "synthetic", Password: "s3cr3t"},
+		{Type: AUTH_BEARER, Token: "t0k3n"},
+		{},
+	}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and auth is sensitive
+	openSloState(t, &openslo)
+	schemaResp := datasource.SchemaResponse{}
+	openslo.Schema(context.Background(), datasource.SchemaRequest{}, &schemaResp)
+	auth, diags := schemaResp.Schema.AttributeAtPath(context.Background(), path.Root("extension_httpmonitor").AtMapKey("orders-api").AtName("requests").AtListIndex(0).AtName("auth"))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !auth.IsSensitive() {
+		t.Error("Expected auth to be sensitive")
+	}
+}
+
+func TestSyntheticsAuth_shouldIgnore_unsetSecretReferences(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsAuthYamlSpec, "%s", "/nonexistent/token", 1), &diag.Diagnostics{})
+
+	// then the rendered probes do not need the secrets
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(openslo.Extension_k6_scripts["orders-api"], `__ENV["TEST_SYNTHETICS_PASSWORD"]`) {
+		t.Errorf("Expected the k6 script to read the password from the environment, got %s", openslo.Extension_k6_scripts["orders-api"])
+	}
+
+	// and the synthetics check reports them
+	check := SyntheticsCheckDataSource{}
+	diagnostics := diag.Diagnostics{}
+	err = check.CheckSynthetics(context.Background(), strings.Replace(syntheticsAuthYamlSpec, "%s", "/nonexistent/token", 1), &diagnostics)
+	if err == nil || !strings.Contains(err.Error(), "http monitor orders-api requests[0]: auth: password: bad secret reference") {
+		t.Errorf("Expected a bad secret reference error, got %v", err)
+	}
+}
+
+func TestSyntheticsAuth_shouldbeError_invalidAuth(t *testing.T) {
+	cases := []struct {
+		auth     AuthModel
+		expected string
+	}{
+		{AuthModel{Type: "digest"}, "type digest is not supported"},
+		{AuthModel{Type: AUTH_BASIC, Username: "synthetic"}, "password is required for basic auth"},
+		{AuthModel{Type: AUTH_BEARER}, "token is required for bearer auth"},
+		{AuthModel{Type: AUTH_OAUTH2, TokenUrl: "https://auth.example.com/token", ClientId: "synthetic"}, "clientSecret is required for oauth2 auth"},
+		{AuthModel{Type: AUTH_BEARER, Token: "env:TEST_SYNTHETICS_UNSET_TOKEN"}, "token: bad secret reference"},
+	}
+
+	for _, c := range cases {
+		// when
+		_, err := resolveAuth(c.auth)
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestSyntheticsAuth_shouldAuthenticate_requests(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, ok := r.BasicAuth()
+		if !ok || clientId != "synthetic" || clientSecret != "s3cr3t" || r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "orders:read" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "t0k3n", "token_type": "bearer"}`))
+	})
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if r.Header.Get("Authorization") != "Bearer t0k3n" && !(ok && username == "synthetic" && password == "s3cr3t") {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	cases := []AuthModel{
+		{Type: AUTH_BASIC, Username: "synthetic", Password: "s3cr3t"},
+		{Type: AUTH_BEARER, Token: "t0k3n"},
+		{Type: AUTH_OAUTH2, TokenUrl: server.URL + "/token", ClientId: "synthetic", ClientSecret: "s3cr3t", Scopes: []string{"orders:read"}},
+	}
+
+	for _, auth := range cases {
+		// when
+		checker := SyntheticsChecker{Client: server.Client()}
+		result := checker.CheckRequest(context.Background(), "orders-api", server.URL, 0, RequestModel{Path: "/orders", Auth: auth})
+
+		// then
+		if !result.Passed {
+			t.Errorf("Expected %s auth to pass, got %v", auth.Type, result.Failures)
+		}
+	}
+
+	// and a rejected client fails the request
+	checker := SyntheticsChecker{Client: server.Client()}
+	result := checker.CheckRequest(context.Background(), "orders-api", server.URL, 0, RequestModel{Path: "/orders", Auth: AuthModel{Type: AUTH_OAUTH2, TokenUrl: server.URL + "/token", ClientId: "synthetic", ClientSecret: "wrong"}})
+	if diff := deep.Equal(result.Failures, []string{"oauth2 token: token endpoint returned status code 401"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+const syntheticsAuthRenderYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: orders-api
+spec:
+  url: https://orders.example.com
+  requests:
+  - name: basic
+    path: /orders
+    auth:
+      type: basic
+      username: synthetic
+      password: env:TEST_SYNTHETICS_PASSWORD
+  - name: bearer
+    path: /orders
+    auth:
+      type: bearer
+      token: file:%s
+  - name: oauth2
+    path: /orders
+    auth:
+      type: oauth2
+      tokenUrl: https://auth.example.com/token
+      clientId: synthetic
+      clientSecret: plain-secret
+`
+
+func TestSyntheticsAuth_shouldRender_secretReferences(t *testing.T) {
+	// given
+	t.Setenv("TEST_SYNTHETICS_PASSWORD", "s3cr3t")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("t0k3n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsAuthRenderYamlSpec, "%s", tokenFile, 1), &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and k6 reads the secrets from the environment and files, never the resolved values
+	script := openslo.Extension_k6_scripts["orders-api"]
+	for _, expected := range []string{
+		`import encoding from "k6/encoding";`,
+		`const secrets = [open(` + jsLiteral(tokenFile) + `).trim()];`,
+		`"Authorization":"Basic " + encoding.b64encode("synthetic" + ":" + __ENV["TEST_SYNTHETICS_PASSWORD"])`,
+		`"Authorization":"Bearer " + secrets[0]`,
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected the k6 script to contain %s, but got %s", expected, script)
+		}
+	}
+	for _, secret := range []string{"s3cr3t", "t0k3n", "plain-secret"} {
+		for name, output := range map[string]string{"k6": script, "blackbox": openslo.Extension_blackbox_exporter_config, "dynatrace": openslo.Extension_dynatrace_http_monitors["orders-api"]} {
+			if strings.Contains(output, secret) {
+				t.Errorf("Expected the %s output not to contain the secret %s", name, secret)
+			}
+		}
+	}
+
+	// and blackbox reads the token file
+	var config BlackboxConfig
+	if err := yaml.Unmarshal([]byte(openslo.Extension_blackbox_exporter_config), &config); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(config.Modules["openslo_orders_api_bearer"].Http.Authorization, &BlackboxAuthorization{Type: "Bearer", CredentialsFile: tokenFile}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the secrets each backend cannot read are reported
+	for summary, details := range map[string][]string{
+		"Cannot render k6 auth, skipping":        {"http monitor orders-api request oauth2: auth.clientSecret must be an env: or file: reference"},
+		"Cannot render blackbox auth, skipping":  {"http monitor orders-api request basic: auth.password must be a file: reference", "http monitor orders-api request oauth2: auth.clientSecret must be a file: reference"},
+		"Cannot render Dynatrace auth, skipping": {"basic", "bearer", "oauth2"},
+	} {
+		warnings := warningsWithSummary(diagnostics, summary)
+		if len(warnings) != len(details) {
+			t.Errorf("Expected %d %q warnings, got %v", len(details), summary, warnings)
+			continue
+		}
+		for i, detail := range details {
+			if !strings.Contains(warnings[i].Detail(), detail) {
+				t.Errorf("Expected %q warning to contain %s, got %s", summary, detail, warnings[i].Detail())
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	BLACKBOX_DEFAULT_EXPORTER_ADDRESS = "127.0.0.1:9115"
+	BLACKBOX_PROBER_HTTP              = "http"
+	BLACKBOX_METRICS_PATH             = "/probe"
+	PROMETHEUS_LABEL_MONITOR          = "openslo_monitor"
+	PROMETHEUS_LABEL_REQUEST          = "openslo_request"
+)
+
+var blackboxModuleInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+type BlackboxConfig struct {
+	Modules map[string]BlackboxModule `yaml:"modules"`
+}
+
+type BlackboxModule struct {
+	Prober  string            `yaml:"prober"`
+	Timeout string            `yaml:"timeout,omitempty"`
+	Http    BlackboxHttpProbe `yaml:"http"`
+}
+
+type BlackboxHttpProbe struct {
+	Method                     string                  `yaml:"method"`
+	Headers                    map[string]string       `yaml:"headers,omitempty"`
+	Body                       string                  `yaml:"body,omitempty"`
+	ValidStatusCodes           []int                   `yaml:"valid_status_codes,omitempty,flow"`
+	FailIfBodyMatchesRegexp    []string                `yaml:"fail_if_body_matches_regexp,omitempty"`
+	FailIfBodyNotMatchesRegexp []string                `yaml:"fail_if_body_not_matches_regexp,omitempty"`
+	FailIfHeaderNotMatches     []BlackboxHeaderMatcher `yaml:"fail_if_header_not_matches,omitempty"`
+	BasicAuth                  *BlackboxBasicAuth      `yaml:"basic_auth,omitempty"`
+	Authorization              *BlackboxAuthorization  `yaml:"authorization,omitempty"`
+	OAuth2                     *BlackboxOAuth2         `yaml:"oauth2,omitempty"`
+}
+
+type BlackboxBasicAuth struct {
+	Username     string `yaml:"username,omitempty"`
+	UsernameFile string `yaml:"username_file,omitempty"`
+	PasswordFile string `yaml:"password_file"`
+}
+
+type BlackboxAuthorization struct {
+	Type            string `yaml:"type"`
+	CredentialsFile string `yaml:"credentials_file"`
+}
+
+type BlackboxOAuth2 struct {
+	ClientId         string   `yaml:"client_id"`
+	ClientSecretFile string   `yaml:"client_secret_file"`
+	TokenUrl         string   `yaml:"token_url"`
+	Scopes           []string `yaml:"scopes,omitempty"`
+}
+
+type BlackboxHeaderMatcher struct {
+	Header       string `yaml:"header"`
+	Regexp       string `yaml:"regexp"`
+	AllowMissing bool   `yaml:"allow_missing"`
+}
+
+type PrometheusScrapeConfigFile struct {
+	ScrapeConfigs []PrometheusScrapeConfig `yaml:"scrape_configs"`
+}
+
+type PrometheusScrapeConfig struct {
+	JobName        string                    `yaml:"job_name"`
+	ScrapeInterval string                    `yaml:"scrape_interval,omitempty"`
+	ScrapeTimeout  string                    `yaml:"scrape_timeout,omitempty"`
+	MetricsPath    string                    `yaml:"metrics_path"`
+	StaticConfigs  []PrometheusStaticConfig  `yaml:"static_configs"`
+	RelabelConfigs []PrometheusRelabelConfig `yaml:"relabel_configs"`
+}
+
+type PrometheusStaticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+type PrometheusRelabelConfig struct {
+	SourceLabels []string `yaml:"source_labels,omitempty,flow"`
+	TargetLabel  string   `yaml:"target_label"`
+	Replacement  string   `yaml:"replacement,omitempty"`
+}
+
+// SyntheticsExtensionRenderBlackbox renders a blackbox_exporter module per HTTP monitor request, and a Prometheus scrape
+// config per enabled HTTP monitor probing its requests through the blackbox exporter, at the monitor frequency and
+// timeout. Assertions without a blackbox equivalent, auth secrets that are not file: references, and requests using
+// extracted variables, produce a warning.
+func (d *OpenSloDataSource) SyntheticsExtensionRenderBlackbox(diagnostics *diag.Diagnostics) error {
+	exporterAddress := d.Extension_blackbox_exporter_address.ValueString()
+	if exporterAddress == "" {
+		exporterAddress = BLACKBOX_DEFAULT_EXPORTER_ADDRESS
+	}
+
+	config := BlackboxConfig{Modules: map[string]BlackboxModule{}}
+	scrapeConfigs := PrometheusScrapeConfigFile{ScrapeConfigs: []PrometheusScrapeConfig{}}
+	for _, monitorName := range sortedKeys(d.Extension_httpmonitor) {
+		monitor := d.Extension_httpmonitor[monitorName]
+		if !monitorEnabled(monitor.Enabled) {
+			continue
+		}
+		scrapeConfig := BlackboxScrapeConfig(monitorName, monitor, exporterAddress)
+		for i, request := range monitor.Requests {
+			requestName := RequestName(i, request)
+			if references := RequestVariableReferences(request); len(references) > 0 {
+				// Each target is probed on its own, values of the previous requests are not available
+				diagnostics.AddWarning("Cannot render blackbox request, skipping",
+					fmt.Sprintf("http monitor %s request %s uses variables %v extracted by previous requests", monitorName, requestName, references))
+				continue
+			}
+			moduleName := BlackboxModuleName(monitorName, requestName)
+			module, unsupported := BlackboxModuleOf(request)
+			for _, field := range unsupported {
+				diagnostics.AddWarning("Cannot render blackbox assertion, skipping",
+					fmt.Sprintf("http monitor %s request %s: expectedResponse.%s is not supported by blackbox_exporter", monitorName, requestName, field))
+			}
+			for _, authError := range BlackboxAuthOf(request.Auth, &module.Http) {
+				diagnostics.AddWarning("Cannot render blackbox auth, skipping",
+					fmt.Sprintf("http monitor %s request %s: %s", monitorName, requestName, authError))
+			}
+			config.Modules[moduleName] = module
+
+			labels := map[string]string{
+				"__param_module":         moduleName,
+				PROMETHEUS_LABEL_MONITOR: monitorName,
+				PROMETHEUS_LABEL_REQUEST: requestName,
+			}
+			if monitor.ServiceRef != "" {
+				labels[PROMETHEUS_LABEL_SERVICE] = monitor.ServiceRef
+			}
+			scrapeConfig.StaticConfigs = append(scrapeConfig.StaticConfigs, PrometheusStaticConfig{
+				Targets: []string{RequestUrl(monitor.Url, request.Path)},
+				Labels:  labels,
+			})
+		}
+		if len(scrapeConfig.StaticConfigs) > 0 {
+			scrapeConfigs.ScrapeConfigs = append(scrapeConfigs.ScrapeConfigs, scrapeConfig)
+		}
+	}
+
+	blackboxYaml, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	scrapeConfigsYaml, err := yaml.Marshal(scrapeConfigs)
+	if err != nil {
+		return err
+	}
+	d.Extension_blackbox_exporter_config = string(blackboxYaml)
+	d.Extension_blackbox_scrape_configs = string(scrapeConfigsYaml)
+	return nil
+}
+
+// BlackboxModuleOf returns the http module of the request, and the expected response fields it cannot check.
+// Without expected codes, the blackbox default of 2xx applies. The maximum latency is the probe timeout.
+func BlackboxModuleOf(request RequestModel) (BlackboxModule, []string) {
+	method := strings.ToUpper(string(request.Method))
+	if method == "" {
+		method = string(GET)
+	}
+	expected := request.ExpectedResponse
+	probe := BlackboxHttpProbe{
+		Method: method,
+		Body:   request.Body,
+	}
+	if len(request.Headers) > 0 {
+		probe.Headers = map[string]string{}
+		for _, header := range request.Headers {
+			probe.Headers[header.Name] = header.Value
+		}
+	}
+
+	probe.ValidStatusCodes = append(probe.ValidStatusCodes, expected.Codes...)
+	for _, codeRange := range expected.CodeRanges {
+		low, high, err := StatusCodeRangeBounds(codeRange)
+		if err != nil {
+			continue
+		}
+		for code := low; code <= high; code++ {
+			probe.ValidStatusCodes = append(probe.ValidStatusCodes, code)
+		}
+	}
+
+	if expected.PayloadContains != "" {
+		probe.FailIfBodyNotMatchesRegexp = append(probe.FailIfBodyNotMatchesRegexp, regexp.QuoteMeta(expected.PayloadContains))
+	}
+	if expected.PayloadMatches != "" {
+		probe.FailIfBodyNotMatchesRegexp = append(probe.FailIfBodyNotMatchesRegexp, expected.PayloadMatches)
+	}
+	if expected.PayloadNotContains != "" {
+		probe.FailIfBodyMatchesRegexp = append(probe.FailIfBodyMatchesRegexp, regexp.QuoteMeta(expected.PayloadNotContains))
+	}
+	for _, header := range expected.Headers {
+		headerRegexp := header.Matches
+		switch {
+		case header.Value != "":
+			headerRegexp = "^" + regexp.QuoteMeta(header.Value) + "$"
+		case headerRegexp == "":
+			headerRegexp = ".*"
+		}
+		probe.FailIfHeaderNotMatches = append(probe.FailIfHeaderNotMatches, BlackboxHeaderMatcher{Header: header.Name, Regexp: headerRegexp})
+	}
+
+	var unsupported []string
+	if len(expected.JsonPath) > 0 {
+		unsupported = append(unsupported, "jsonPath")
+	}
+	if expected.MinCertificateValidity != "" {
+		// Alert on probe_ssl_earliest_cert_expiry instead
+		unsupported = append(unsupported, "minCertificateValidity")
+	}
+	return BlackboxModule{Prober: BLACKBOX_PROBER_HTTP, Timeout: expected.MaxLatency, Http: probe}, unsupported
+}
+
+// BlackboxAuthOf sets the auth of the probe, and returns why it cannot. The exporter reads secrets from files, so they
+// must be file:/path references, the other auth fields must be plain values, or a file: reference for the username.
+func BlackboxAuthOf(auth AuthModel, probe *BlackboxHttpProbe) []string {
+	var errs []string
+	secretFile := func(field string, value string) string {
+		prefix, target, ok := secretReference(value)
+		if !ok || prefix != SECRET_REF_FILE {
+			errs = append(errs, fmt.Sprintf("auth.%s must be a file: reference", field))
+		}
+		return target
+	}
+	plain := func(field string, value string) string {
+		if _, _, ok := secretReference(value); ok {
+			errs = append(errs, fmt.Sprintf("auth.%s must be a plain value", field))
+		}
+		return value
+	}
+
+	var basicAuth *BlackboxBasicAuth
+	var authorization *BlackboxAuthorization
+	var oauth2 *BlackboxOAuth2
+	switch auth.Type {
+	case AUTH_BASIC:
+		basicAuth = &BlackboxBasicAuth{PasswordFile: secretFile("password", auth.Password)}
+		if prefix, target, ok := secretReference(auth.Username); ok && prefix == SECRET_REF_FILE {
+			basicAuth.UsernameFile = target
+		} else {
+			basicAuth.Username = plain("username", auth.Username)
+		}
+	case AUTH_BEARER:
+		authorization = &BlackboxAuthorization{Type: "Bearer", CredentialsFile: secretFile("token", auth.Token)}
+	case AUTH_OAUTH2:
+		oauth2 = &BlackboxOAuth2{
+			ClientId:         plain("clientId", auth.ClientId),
+			ClientSecretFile: secretFile("clientSecret", auth.ClientSecret),
+			TokenUrl:         plain("tokenUrl", auth.TokenUrl),
+			Scopes:           auth.Scopes,
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	probe.BasicAuth = basicAuth
+	probe.Authorization = authorization
+	probe.OAuth2 = oauth2
+	return nil
+}
+
+// BlackboxScrapeConfig returns the scrape config of a monitor, without targets. Targets are probed through the
+// exporter with the module of their __param_module label.
+func BlackboxScrapeConfig(monitorName string, monitor HTTPMonitorModel, exporterAddress string) PrometheusScrapeConfig {
+	return PrometheusScrapeConfig{
+		JobName:        "openslo-blackbox-" + monitorName,
+		ScrapeInterval: FormatPrometheusDuration(monitorDuration(monitor.Frequency, MONITOR_DEFAULT_FREQUENCY)),
+		ScrapeTimeout:  FormatPrometheusDuration(monitorDuration(monitor.Timeout, MONITOR_DEFAULT_TIMEOUT)),
+		MetricsPath:    BLACKBOX_METRICS_PATH,
+		RelabelConfigs: []PrometheusRelabelConfig{
+			{SourceLabels: []string{"__address__"}, TargetLabel: "__param_target"},
+			{SourceLabels: []string{"__param_target"}, TargetLabel: "instance"},
+			{TargetLabel: "__address__", Replacement: exporterAddress},
+		},
+	}
+}
+
+// BlackboxModuleName returns the module name of a monitor request
+func BlackboxModuleName(monitorName string, requestName string) string {
+	return blackboxModuleInvalidChars.ReplaceAllString(fmt.Sprintf("openslo_%s_%s", monitorName, requestName), "_")
+}
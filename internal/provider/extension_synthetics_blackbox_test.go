@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const blackboxYamlSpec = `
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+spec:
+  description: Checkout service
+---
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: checkout-api
+spec:
+  url: https://checkout.example.com/
+  serviceRef: checkout
+  frequency: 1m
+  timeout: 5s
+  requests:
+  - name: login
+    method: post
+    path: /login
+    body: '{"user": "synthetic"}'
+    headers:
+    - name: Content-Type
+      value: application/json
+    expectedResponse:
+      code:
+      - 200
+      - 201
+      payloadContains: token.
+      payloadNotContains: error
+      headers:
+      - name: Content-Type
+        value: application/json
+      - name: X-Request-Id
+      maxLatency: 2s
+  - path: health
+    expectedResponse:
+      payloadMatches: ^ok
+      jsonPath:
+      - path: $.status
+      minCertificateValidity: 14d
+`
+
+const blackboxExpectedConfig = `modules:
+  openslo_checkout_api_1:
+    prober: http
+    http:
+      method: GET
+      fail_if_body_not_matches_regexp:
+      - ^ok
+  openslo_checkout_api_login:
+    prober: http
+    timeout: 2s
+    http:
+      method: POST
+      headers:
+        Content-Type: application/json
+      body: "{\"user\": \"synthetic\"}"
+      valid_status_codes: [200, 201]
+      fail_if_body_matches_regexp:
+      - error
+      fail_if_body_not_matches_regexp:
+      - "token\\."
+      fail_if_header_not_matches:
+      - header: Content-Type
+        regexp: ^application/json$
+        allow_missing: false
+      - header: X-Request-Id
+        regexp: .*
+        allow_missing: false
+`
+
+func TestSyntheticsBlackbox_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{Extension_blackbox_exporter_address: types.StringValue("blackbox-exporter:9115")}
+	err := openslo.GetOpenSloData(blackboxYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(openslo.Extension_blackbox_exporter_config, blackboxExpectedConfig); diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	scrapeConfigs := PrometheusScrapeConfigFile{}
+	if err := yaml.Unmarshal([]byte(openslo.Extension_blackbox_scrape_configs), &scrapeConfigs); err != nil {
+		t.Fatal(err)
+	}
+	expected := PrometheusScrapeConfigFile{ScrapeConfigs: []PrometheusScrapeConfig{{
+		JobName:        "openslo-blackbox-checkout-api",
+		ScrapeInterval: "1m",
+		ScrapeTimeout:  "5s",
+		MetricsPath:    "/probe",
+		StaticConfigs: []PrometheusStaticConfig{
+			{
+				Targets: []string{"https://checkout.example.com/login"},
+				Labels:  map[string]string{"__param_module": "openslo_checkout_api_login", "openslo_monitor": "checkout-api", "openslo_request": "login", "openslo_service": "checkout"},
+			},
+			{
+				Targets: []string{"https://checkout.example.com/health"},
+				Labels:  map[string]string{"__param_module": "openslo_checkout_api_1", "openslo_monitor": "checkout-api", "openslo_request": "1", "openslo_service": "checkout"},
+			},
+		},
+		RelabelConfigs: []PrometheusRelabelConfig{
+			{SourceLabels: []string{"__address__"}, TargetLabel: "__param_target"},
+			{SourceLabels: []string{"__param_target"}, TargetLabel: "instance"},
+			{TargetLabel: "__address__", Replacement: "blackbox-exporter:9115"},
+		},
+	}}}
+	if diff := deep.Equal(scrapeConfigs, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// and unsupported assertions are warnings
+	warnings := warningsWithSummary(diagnostics, "Cannot render blackbox assertion, skipping")
+	if len(warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", warnings)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+func TestSyntheticsBlackbox_shouldExpand_codeRanges(t *testing.T) {
+	// when
+	module, unsupported := BlackboxModuleOf(RequestModel{ExpectedResponse: ResponseModel{Codes: []int{404}, CodeRanges: []string{"200-202"}}})
+
+	// then
+	if diff := deep.Equal(module.Http.ValidStatusCodes, []int{404, 200, 201, 202}); diff != nil {
+		t.Error(diff)
+	}
+	if len(unsupported) != 0 {
+		t.Errorf("Unexpected unsupported fields %v", unsupported)
+	}
+}
+
+func TestSyntheticsBlackbox_shouldSkip_disabledMonitors(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(blackboxYamlSpec, "frequency: 1m", "enabled: false", 1), &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(openslo.Extension_blackbox_scrape_configs, "scrape_configs: []\n"); diff != nil {
+		t.Error(diff)
+	}
+}
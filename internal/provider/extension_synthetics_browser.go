@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	DYNATRACE_MONITOR_TYPE_BROWSER  = "BROWSER"
+	DYNATRACE_BROWSER_SCRIPT_TYPE   = "clickpath"
+	DYNATRACE_EVENT_NAVIGATE        = "navigate"
+	DYNATRACE_EVENT_CLICK           = "click"
+	DYNATRACE_EVENT_KEYSTROKES      = "keystrokes"
+	DYNATRACE_EVENT_JAVASCRIPT      = "javascript"
+	DYNATRACE_WAIT_PAGE_COMPLETE    = "page_complete"
+	DYNATRACE_WAIT_ELEMENT          = "element"
+	DYNATRACE_LOCATOR_CSS           = "css"
+	BROWSER_DEFAULT_ASSERT_SELECTOR = "body"
+)
+
+var browserActions = []BrowserAction{BROWSER_NAVIGATE, BROWSER_CLICK, BROWSER_FILL, BROWSER_WAIT_FOR, BROWSER_ASSERT_TEXT, BROWSER_ASSERT_TITLE, BROWSER_SCREENSHOT}
+
+// DynatraceBrowserMonitor is a Dynatrace synthetic browser monitor, as in the synthetic monitors API
+type DynatraceBrowserMonitor struct {
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	FrequencyMin int                    `json:"frequencyMin"`
+	Enabled      bool                   `json:"enabled"`
+	Locations    []string               `json:"locations"`
+	Script       DynatraceBrowserScript `json:"script"`
+	Tags         []string               `json:"tags"`
+}
+
+type DynatraceBrowserScript struct {
+	Type    string                  `json:"type"`
+	Version string                  `json:"version"`
+	Events  []DynatraceBrowserEvent `json:"events"`
+}
+
+type DynatraceBrowserEvent struct {
+	Type        string                  `json:"type"`
+	Description string                  `json:"description"`
+	Url         string                  `json:"url,omitempty"`
+	Target      *DynatraceBrowserTarget `json:"target,omitempty"`
+	Button      *int                    `json:"button,omitempty"`
+	TextValue   string                  `json:"textValue,omitempty"`
+	JavaScript  string                  `json:"javaScript,omitempty"`
+	Wait        *DynatraceBrowserWait   `json:"wait,omitempty"`
+}
+
+type DynatraceBrowserTarget struct {
+	Locators []DynatraceBrowserLocator `json:"locators"`
+}
+
+type DynatraceBrowserLocator struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type DynatraceBrowserWait struct {
+	WaitFor string                  `json:"waitFor"`
+	Target  *DynatraceBrowserTarget `json:"target,omitempty"`
+}
+
+// ValidateBrowserSteps checks the required fields of every step action. A monitor has either a raw script or steps,
+// steps start on the monitor url unless they navigate first, and navigate to absolute urls without a monitor url.
+func ValidateBrowserSteps(monitor BrowserMonitorModel) error {
+	if len(monitor.Steps) == 0 {
+		return nil
+	}
+	if monitor.Script != "" {
+		return fmt.Errorf("script and steps cannot be combined")
+	}
+	if monitor.Url == "" && monitor.Steps[0].Action != BROWSER_NAVIGATE {
+		return fmt.Errorf("steps[0]: expected a navigate action when the monitor has no url")
+	}
+	for i, step := range monitor.Steps {
+		var required map[string]string
+		switch step.Action {
+		case BROWSER_NAVIGATE:
+			required = map[string]string{"url": step.Url}
+			if step.Url != "" && monitor.Url == "" && !isAbsoluteUrl(step.Url) {
+				return fmt.Errorf("steps[%d]: url must be absolute when the monitor has no url, got %q", i, step.Url)
+			}
+		case BROWSER_CLICK, BROWSER_FILL, BROWSER_WAIT_FOR:
+			required = map[string]string{"selector": step.Selector}
+		case BROWSER_ASSERT_TEXT, BROWSER_ASSERT_TITLE:
+			required = map[string]string{"text": step.Text}
+		case BROWSER_SCREENSHOT:
+		default:
+			return fmt.Errorf("steps[%d]: action %q is not supported, expected one of %v", i, step.Action, browserActions)
+		}
+		for _, field := range sortedKeys(required) {
+			if required[field] == "" {
+				return fmt.Errorf("steps[%d]: %s is required for %s", i, field, step.Action)
+			}
+		}
+	}
+	return nil
+}
+
+// SyntheticsExtensionRenderBrowser renders a Playwright test per enabled browser monitor with steps, and a Dynatrace
+// clickpath monitor per browser monitor with steps. Monitors with a raw script are left as is. Steps without a
+// Dynatrace event, and Playwright tests of monitors with locations, produce a warning.
+func (d *OpenSloDataSource) SyntheticsExtensionRenderBrowser(diagnostics *diag.Diagnostics) error {
+	d.Extension_playwright_scripts = map[string]string{}
+	d.Extension_dynatrace_browser_monitors = map[string]string{}
+	for _, monitorName := range sortedKeys(d.Extension_browsermonitor) {
+		monitor := d.Extension_browsermonitor[monitorName]
+		if len(monitor.Steps) == 0 {
+			continue
+		}
+		if monitorEnabled(monitor.Enabled) {
+			if len(monitor.Locations) > 0 {
+				diagnostics.AddWarning("Playwright ignores locations", fmt.Sprintf("browser monitor %s locations are not supported by playwright", monitorName))
+			}
+			d.Extension_playwright_scripts[monitorName] = PlaywrightScript(monitorName, monitor)
+		}
+
+		dynatraceMonitor, unsupported := DynatraceBrowserMonitorOf(monitorName, monitor)
+		for _, step := range unsupported {
+			diagnostics.AddWarning("Cannot render dynatrace browser step, skipping", fmt.Sprintf("browser monitor %s %s has no clickpath event", monitorName, step))
+		}
+		monitorJson, err := json.MarshalIndent(dynatraceMonitor, "", "  ")
+		if err != nil {
+			return fmt.Errorf("browser monitor %s: %w", monitorName, err)
+		}
+		d.Extension_dynatrace_browser_monitors[monitorName] = string(monitorJson)
+	}
+	return nil
+}
+
+// PlaywrightScript returns the Playwright test running the steps of the monitor, with the monitor timeout and retries
+func PlaywrightScript(monitorName string, monitor BrowserMonitorModel) string {
+	var script strings.Builder
+	script.WriteString("const { test, expect } = require(\"@playwright/test\");\n\n")
+	script.WriteString(fmt.Sprintf("// Generated from the %s BrowserMonitor\n\n", monitorName))
+	if monitor.Retries > 0 {
+		script.WriteString(fmt.Sprintf("test.describe.configure({ retries: %d });\n\n", monitor.Retries))
+	}
+	script.WriteString(fmt.Sprintf("test(%s, async ({ page }) => {\n", jsLiteral(monitorName)))
+	if timeout, err := ParseOpenSloDuration(monitor.Timeout); err == nil {
+		script.WriteString(fmt.Sprintf("  test.setTimeout(%d);\n", timeout.Milliseconds()))
+	}
+	for _, step := range browserSteps(monitor) {
+		locator := fmt.Sprintf("page.locator(%s)", jsLiteral(step.Selector))
+		switch step.Action {
+		case BROWSER_NAVIGATE:
+			script.WriteString(fmt.Sprintf("  await page.goto(%s);\n", jsLiteral(step.Url)))
+		case BROWSER_CLICK:
+			script.WriteString(fmt.Sprintf("  await %s.click();\n", locator))
+		case BROWSER_FILL:
+			script.WriteString(fmt.Sprintf("  await %s.fill(%s);\n", locator, jsLiteral(step.Value)))
+		case BROWSER_WAIT_FOR:
+			script.WriteString(fmt.Sprintf("  await %s.waitFor();\n", locator))
+		case BROWSER_ASSERT_TEXT:
+			script.WriteString(fmt.Sprintf("  await expect(page.locator(%s)).toContainText(%s);\n", jsLiteral(assertTextSelector(step.BrowserStepModel)), jsLiteral(step.Text)))
+		case BROWSER_ASSERT_TITLE:
+			script.WriteString(fmt.Sprintf("  await expect(page).toHaveTitle(%s);\n", jsLiteral(step.Text)))
+		case BROWSER_SCREENSHOT:
+			name := step.Name
+			if name == "" {
+				name = fmt.Sprintf("%s-%d", monitorName, step.index)
+			}
+			script.WriteString(fmt.Sprintf("  await page.screenshot({ path: %s });\n", jsLiteral(name+".png")))
+		}
+	}
+	script.WriteString("});\n")
+	return script.String()
+}
+
+// assertTextSelector returns the selector of the element an assertText step reads, the whole page body by default
+func assertTextSelector(step BrowserStepModel) string {
+	if step.Selector == "" {
+		return BROWSER_DEFAULT_ASSERT_SELECTOR
+	}
+	return step.Selector
+}
+
+// DynatraceBrowserMonitorOf returns the Dynatrace clickpath monitor running the steps of the monitor, and the steps
+// it cannot run. Waits are set on the previous event, assertions are javascript events failing the monitor.
+func DynatraceBrowserMonitorOf(monitorName string, monitor BrowserMonitorModel) (DynatraceBrowserMonitor, []string) {
+	name := monitor.Metadata.DisplayName
+	if name == "" {
+		name = monitorName
+	}
+	tags := []string{DYNATRACE_TAG_MONITOR}
+	if monitor.ServiceRef != "" {
+		tags = append(tags, fmt.Sprintf("%s:%s", DYNATRACE_TAG_SERVICE, monitor.ServiceRef))
+	}
+
+	events := []DynatraceBrowserEvent{}
+	var unsupported []string
+	leftButton := 0
+	for _, step := range browserSteps(monitor) {
+		target := &DynatraceBrowserTarget{Locators: []DynatraceBrowserLocator{{Type: DYNATRACE_LOCATOR_CSS, Value: step.Selector}}}
+		switch step.Action {
+		case BROWSER_NAVIGATE:
+			events = append(events, DynatraceBrowserEvent{
+				Type:        DYNATRACE_EVENT_NAVIGATE,
+				Description: fmt.Sprintf("Navigate to %s", step.Url),
+				Url:         step.Url,
+				Wait:        &DynatraceBrowserWait{WaitFor: DYNATRACE_WAIT_PAGE_COMPLETE},
+			})
+		case BROWSER_CLICK:
+			events = append(events, DynatraceBrowserEvent{
+				Type:        DYNATRACE_EVENT_CLICK,
+				Description: fmt.Sprintf("Click %s", step.Selector),
+				Target:      target,
+				Button:      &leftButton,
+			})
+		case BROWSER_FILL:
+			events = append(events, DynatraceBrowserEvent{
+				Type:        DYNATRACE_EVENT_KEYSTROKES,
+				Description: fmt.Sprintf("Fill %s", step.Selector),
+				Target:      target,
+				TextValue:   step.Value,
+			})
+		case BROWSER_WAIT_FOR:
+			events[len(events)-1].Wait = &DynatraceBrowserWait{WaitFor: DYNATRACE_WAIT_ELEMENT, Target: target}
+		case BROWSER_ASSERT_TEXT:
+			selector := assertTextSelector(step.BrowserStepModel)
+			events = append(events, DynatraceBrowserEvent{
+				Type:        DYNATRACE_EVENT_JAVASCRIPT,
+				Description: fmt.Sprintf("Assert %s contains %s", selector, step.Text),
+				JavaScript: fmt.Sprintf("var element = document.querySelector(%s);\nif (!element || element.textContent.indexOf(%s) < 0) {\n  api.fail(%s);\n}",
+					jsLiteral(selector), jsLiteral(step.Text), jsLiteral(fmt.Sprintf("%s does not contain %s", selector, step.Text))),
+			})
+		case BROWSER_ASSERT_TITLE:
+			events = append(events, DynatraceBrowserEvent{
+				Type:        DYNATRACE_EVENT_JAVASCRIPT,
+				Description: fmt.Sprintf("Assert title is %s", step.Text),
+				JavaScript: fmt.Sprintf("if (document.title !== %s) {\n  api.fail(%s);\n}",
+					jsLiteral(step.Text), jsLiteral(fmt.Sprintf("title is not %s", step.Text))),
+			})
+		case BROWSER_SCREENSHOT:
+			// Dynatrace captures a screenshot of failed executions
+			unsupported = append(unsupported, fmt.Sprintf("steps[%d] %s", step.index, step.Action))
+		}
+	}
+
+	return DynatraceBrowserMonitor{
+		Name:         name,
+		Type:         DYNATRACE_MONITOR_TYPE_BROWSER,
+		FrequencyMin: monitorFrequencyMinutes(monitor.Frequency),
+		Enabled:      monitorEnabled(monitor.Enabled),
+		Locations:    append([]string{}, monitor.Locations...),
+		Script: DynatraceBrowserScript{
+			Type:    DYNATRACE_BROWSER_SCRIPT_TYPE,
+			Version: DYNATRACE_SCRIPT_VERSION,
+			Events:  events,
+		},
+		Tags: tags,
+	}, unsupported
+}
+
+// browserStep is a step of the monitor with its index in the monitor steps, -1 for the navigation to the monitor url
+type browserStep struct {
+	BrowserStepModel
+	index int
+}
+
+// browserSteps returns the steps of the monitor starting with a navigation, to the monitor url unless the first step
+// navigates. Navigation urls are relative to the monitor url.
+func browserSteps(monitor BrowserMonitorModel) []browserStep {
+	steps := []browserStep{}
+	if len(monitor.Steps) > 0 && monitor.Steps[0].Action != BROWSER_NAVIGATE {
+		steps = append(steps, browserStep{BrowserStepModel{Action: BROWSER_NAVIGATE, Url: monitor.Url}, -1})
+	}
+	for i, step := range monitor.Steps {
+		if step.Action == BROWSER_NAVIGATE && !isAbsoluteUrl(step.Url) {
+			step.Url = RequestUrl(monitor.Url, step.Url)
+		}
+		steps = append(steps, browserStep{step, i})
+	}
+	return steps
+}
+
+func isAbsoluteUrl(url string) bool {
+	return strings.Contains(url, "://")
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const syntheticsBrowserYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: BrowserMonitor
+metadata:
+  name: checkout-flow
+  displayName: Checkout flow
+spec:
+  url: https://shop.example.com
+  retries: 1
+  timeout: 30s
+  steps:
+  - action: click
+    selector: "#login"
+  - action: fill
+    selector: input[name="user"]
+    value: synthetic
+  - action: navigate
+    url: /cart
+  - action: waitFor
+    selector: .cart
+  - action: assertText
+    selector: h1
+    text: Your cart
+  - action: assertTitle
+    text: Checkout
+  - action: screenshot
+    name: cart
+`
+
+const playwrightExpectedScript = `const { test, expect } = require("@playwright/test");
+
+// Generated from the checkout-flow BrowserMonitor
+
+test.describe.configure({ retries: 1 });
+
+test("checkout-flow", async ({ page }) => {
+  test.setTimeout(30000);
+  await page.goto("https://shop.example.com");
+  await page.locator("#login").click();
+  await page.locator("input[name=\"user\"]").fill("synthetic");
+  await page.goto("https://shop.example.com/cart");
+  await page.locator(".cart").waitFor();
+  await expect(page.locator("h1")).toContainText("Your cart");
+  await expect(page).toHaveTitle("Checkout");
+  await page.screenshot({ path: "cart.png" });
+});
+`
+
+func TestSyntheticsBrowser_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(syntheticsBrowserYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(openslo.Extension_playwright_scripts, map[string]string{"checkout-flow": playwrightExpectedScript}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	var monitor DynatraceBrowserMonitor
+	if err := json.Unmarshal([]byte(openslo.Extension_dynatrace_browser_monitors["checkout-flow"]), &monitor); err != nil {
+		t.Fatal(err)
+	}
+	leftButton := 0
+	expected := DynatraceBrowserMonitor{
+		Name:         "Checkout flow",
+		Type:         "BROWSER",
+		FrequencyMin: 15,
+		Enabled:      true,
+		Locations:    []string{},
+		Script: DynatraceBrowserScript{
+			Type:    "clickpath",
+			Version: "1.0",
+			Events: []DynatraceBrowserEvent{
+				{Type: "navigate", Description: "Navigate to https://shop.example.com", Url: "https://shop.example.com", Wait: &DynatraceBrowserWait{WaitFor: "page_complete"}},
+				{Type: "click", Description: "Click #login", Target: &DynatraceBrowserTarget{Locators: []DynatraceBrowserLocator{{Type: "css", Value: "#login"}}}, Button: &leftButton},
+				{Type: "keystrokes", Description: `Fill input[name="user"]`, Target: &DynatraceBrowserTarget{Locators: []DynatraceBrowserLocator{{Type: "css", Value: `input[name="user"]`}}}, TextValue: "synthetic"},
+				{
+					Type:        "navigate",
+					Description: "Navigate to https://shop.example.com/cart",
+					Url:         "https://shop.example.com/cart",
+					Wait:        &DynatraceBrowserWait{WaitFor: "element", Target: &DynatraceBrowserTarget{Locators: []DynatraceBrowserLocator{{Type: "css", Value: ".cart"}}}},
+				},
+				{
+					Type:        "javascript",
+					Description: "Assert h1 contains Your cart",
+					JavaScript:  "var element = document.querySelector(\"h1\");\nif (!element || element.textContent.indexOf(\"Your cart\") < 0) {\n  api.fail(\"h1 does not contain Your cart\");\n}",
+				},
+				{
+					Type:        "javascript",
+					Description: "Assert title is Checkout",
+					JavaScript:  "if (document.title !== \"Checkout\") {\n  api.fail(\"title is not Checkout\");\n}",
+				},
+			},
+		},
+		Tags: []string{"openslo"},
+	}
+	if diff := deep.Equal(monitor, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the screenshot is a warning
+	warnings := warningsWithSummary(diagnostics, "Cannot render dynatrace browser step, skipping")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "steps[6] screenshot") {
+		t.Errorf("Expected 1 warning naming steps[6], got %v", warnings)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+func TestSyntheticsBrowser_shouldbeError_invalidSteps(t *testing.T) {
+	cases := []struct {
+		replaced string
+		by       string
+		expected string
+	}{
+		{"action: click", "action: hover", `browser monitor checkout-flow: steps[0]: action "hover" is not supported`},
+		{"    selector: \"#login\"\n", "", "steps[0]: selector is required for click"},
+		{"    text: Checkout\n", "", "steps[5]: text is required for assertTitle"},
+		{"    url: /cart\n", "", "steps[2]: url is required for navigate"},
+		{"  url: https://shop.example.com\n", "", "steps[0]: expected a navigate action when the monitor has no url"},
+		{"  steps:\n", "  script: console.log(1)\n  steps:\n", "script and steps cannot be combined"},
+		{"  url: https://shop.example.com\n  retries: 1\n  timeout: 30s\n  steps:\n", "  retries: 1\n  timeout: 30s\n  steps:\n  - action: navigate\n    url: /home\n", `steps[0]: url must be absolute when the monitor has no url, got "/home"`},
+	}
+
+	for _, c := range cases {
+		// when
+		err := (&OpenSloDataSource{}).GetOpenSloData(strings.Replace(syntheticsBrowserYamlSpec, c.replaced, c.by, 1), &diag.Diagnostics{})
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestSyntheticsBrowser_shouldNameScreenshots_byStepIndex(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsBrowserYamlSpec, "    name: cart\n", "", 1), &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(openslo.Extension_playwright_scripts["checkout-flow"], `await page.screenshot({ path: "checkout-flow-6.png" });`) {
+		t.Errorf("Expected the screenshot to be named after steps[6], got %s", openslo.Extension_playwright_scripts["checkout-flow"])
+	}
+}
+
+func TestSyntheticsBrowser_shouldAssertText_inBodyWithoutSelector(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsBrowserYamlSpec, "    selector: h1\n", "", 1), &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `  await expect(page.locator("body")).toContainText("Your cart");` + "\n"
+	if !strings.Contains(openslo.Extension_playwright_scripts["checkout-flow"], expected) {
+		t.Errorf("Expected the playwright script to contain %q, got %s", expected, openslo.Extension_playwright_scripts["checkout-flow"])
+	}
+	if !strings.Contains(openslo.Extension_dynatrace_browser_monitors["checkout-flow"], `document.querySelector(\"body\")`) {
+		t.Errorf("Expected the dynatrace monitor to read the body, got %s", openslo.Extension_dynatrace_browser_monitors["checkout-flow"])
+	}
+}
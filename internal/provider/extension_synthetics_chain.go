@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	variableNameRegex      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variableReferenceRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+)
+
+// ValidateRequestChain checks the extractors of the requests, and that the variables referenced with {{name}} in the
+// path, headers and body of a request are extracted by a previous request
+func ValidateRequestChain(requests []RequestModel) error {
+	extracted := map[string]bool{}
+	for i, request := range requests {
+		for _, name := range RequestVariableReferences(request) {
+			if !extracted[name] {
+				return fmt.Errorf("requests[%d]: variable %s is not extracted by a previous request", i, name)
+			}
+		}
+		for j, extractor := range request.Extract {
+			if err := validateExtractor(extractor); err != nil {
+				return fmt.Errorf("requests[%d]: extract[%d]: %w", i, j, err)
+			}
+			extracted[extractor.Name] = true
+		}
+	}
+	return nil
+}
+
+func validateExtractor(extractor ExtractorModel) error {
+	if !variableNameRegex.MatchString(extractor.Name) {
+		return fmt.Errorf("name: expected a letter or underscore followed by letters, digits or underscores, got %q", extractor.Name)
+	}
+	sources := 0
+	for _, source := range []string{extractor.JsonPath, extractor.Header, extractor.Regex} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("expected exactly one of jsonPath, header or regex")
+	}
+	if extractor.JsonPath != "" {
+		if _, err := ParseJsonPath(extractor.JsonPath); err != nil {
+			return fmt.Errorf("jsonPath: %w", err)
+		}
+	}
+	if extractor.Regex != "" {
+		if _, err := regexp.Compile(extractor.Regex); err != nil {
+			return fmt.Errorf("regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// RequestName returns the name of the request, or its index when unnamed
+func RequestName(index int, request RequestModel) string {
+	if request.Name == "" {
+		return strconv.Itoa(index)
+	}
+	return request.Name
+}
+
+// ValidateRequestNames checks that the requests have unique names, unnamed requests being named by their index
+func ValidateRequestNames(requests []RequestModel) error {
+	indexes := map[string]int{}
+	for i, request := range requests {
+		name := RequestName(i, request)
+		if j, ok := indexes[name]; ok {
+			return fmt.Errorf("requests[%d]: name %s is already used by requests[%d]", i, name, j)
+		}
+		indexes[name] = i
+	}
+	return nil
+}
+
+// RequestVariableReferences returns the variables referenced by the path, headers and body of the request, in order
+func RequestVariableReferences(request RequestModel) []string {
+	var names []string
+	values := []string{request.Path, request.Body}
+	for _, header := range request.Headers {
+		values = append(values, header.Value)
+	}
+	for _, value := range values {
+		for _, match := range variableReferenceRegex.FindAllStringSubmatch(value, -1) {
+			if !containsString(names, match[1]) {
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// SubstituteVariables returns the request with the {{name}} references of its path, headers and body replaced by the
+// variable values, path escaped in the path. Unknown variables are left as is.
+func SubstituteVariables(request RequestModel, variables map[string]string) RequestModel {
+	request.Path = substituteVariables(request.Path, variables, url.PathEscape)
+	request.Body = substituteVariables(request.Body, variables, nil)
+	headers := make([]HeaderModel, len(request.Headers))
+	for i, header := range request.Headers {
+		headers[i] = HeaderModel{Name: header.Name, Value: substituteVariables(header.Value, variables, nil)}
+	}
+	if request.Headers != nil {
+		request.Headers = headers
+	}
+	return request
+}
+
+func substituteVariables(value string, variables map[string]string, escape func(string) string) string {
+	return variableReferenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		name := variableReferenceRegex.FindStringSubmatch(reference)[1]
+		if variable, ok := variables[name]; ok {
+			if escape != nil {
+				return escape(variable)
+			}
+			return variable
+		}
+		return reference
+	})
+}
+
+// ExtractVariables returns the values of the extractors read from the response, and a failure per missing value.
+// Regexes extract their first group, or the whole match without groups.
+func ExtractVariables(extractors []ExtractorModel, response SyntheticsResponse) (map[string]string, []string) {
+	variables := map[string]string{}
+	var failures []string
+	var document interface{}
+	var documentErr error
+	parsed := false
+	for _, extractor := range extractors {
+		switch {
+		case extractor.JsonPath != "":
+			if !parsed {
+				documentErr = json.Unmarshal([]byte(response.Payload), &document)
+				parsed = true
+			}
+			segments, _ := ParseJsonPath(extractor.JsonPath)
+			value, ok := JsonPathLookup(document, segments)
+			if documentErr != nil || !ok {
+				failures = append(failures, fmt.Sprintf("extract %s: %s is missing", extractor.Name, extractor.JsonPath))
+				continue
+			}
+			variables[extractor.Name] = jsonPathValueString(value)
+		case extractor.Header != "":
+			if len(response.Headers.Values(extractor.Header)) == 0 {
+				failures = append(failures, fmt.Sprintf("extract %s: header %s is missing", extractor.Name, extractor.Header))
+				continue
+			}
+			variables[extractor.Name] = response.Headers.Get(extractor.Header)
+		case extractor.Regex != "":
+			match := regexp.MustCompile(extractor.Regex).FindStringSubmatch(response.Payload)
+			if match == nil {
+				failures = append(failures, fmt.Sprintf("extract %s: payload does not match %q", extractor.Name, extractor.Regex))
+				continue
+			}
+			variables[extractor.Name] = match[extractorRegexGroup(extractor.Regex)]
+		}
+	}
+	return variables, failures
+}
+
+// extractorRegexGroup returns the group of the regex holding the extracted value
+func extractorRegexGroup(regex string) int {
+	if regexp.MustCompile(regex).NumSubexp() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// jsonPathAccessor returns the javascript property accessors of JSON path segments, e.g. ["orders"][0]
+func jsonPathAccessor(segments []interface{}) string {
+	var accessor strings.Builder
+	for _, segment := range segments {
+		accessor.WriteString(fmt.Sprintf("[%s]", jsLiteral(segment)))
+	}
+	return accessor.String()
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const syntheticsChainYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: orders-api
+spec:
+  url: {{url}}
+  requests:
+  - name: login
+    method: POST
+    path: /login
+    extract:
+    - name: token
+      jsonPath: $.auth.token
+    - name: session
+      header: X-Session
+    - name: csrf
+      regex: csrf=([a-z]+)
+  - name: orders
+    path: /orders/{{ csrf }}
+    headers:
+    - name: Authorization
+      value: Bearer {{token}}
+    - name: X-Session
+      value: "{{session}}"
+    expectedResponse:
+      code:
+      - 200
+`
+
+func TestSyntheticsChain_shouldCheck_chainedRequests(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Header().Set("X-Session", "s1")
+			_, _ = w.Write([]byte(`{"auth": {"token": "abc"}, "form": "csrf=xyz"}`))
+		case "/orders/xyz":
+			if r.Header.Get("Authorization") != "Bearer abc" || r.Header.Get("X-Session") != "s1" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.ReplaceAll(syntheticsChainYamlSpec, "{{url}}", server.URL), &diag.Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// when
+	checker := SyntheticsChecker{Client: server.Client()}
+	results := checker.CheckHttpMonitors(context.Background(), openslo.Extension_httpmonitor)
+
+	// then
+	if diff := deep.Equal(results["orders-api/login"].Variables, map[string]string{"token": "abc", "session": "s1", "csrf": "xyz"}); diff != nil {
+		t.Error(diff)
+	}
+	if !results["orders-api/login"].Passed {
+		t.Errorf("Expected the login request to pass, got %v", results["orders-api/login"].Failures)
+	}
+	orders := results["orders-api/orders"]
+	if !orders.Passed || orders.Url != server.URL+"/orders/{{ csrf }}" {
+		t.Errorf("Expected the chained request to pass, got %v on %s", orders.Failures, orders.Url)
+	}
+}
+
+func TestSyntheticsChain_shouldEscape_pathVariables(t *testing.T) {
+	// when
+	request := SubstituteVariables(RequestModel{
+		Path:    "/orders/{{id}}?token={{token}}",
+		Headers: []HeaderModel{{Name: "X-Id", Value: "{{id}}"}},
+		Body:    `{"id": "{{id}}"}`,
+	}, map[string]string{"id": "a/b?c#d", "token": "t0k3n"})
+
+	// then
+	if diff := deep.Equal(request, RequestModel{
+		Path:    "/orders/a%2Fb%3Fc%23d?token=t0k3n",
+		Headers: []HeaderModel{{Name: "X-Id", Value: "a/b?c#d"}},
+		Body:    `{"id": "a/b?c#d"}`,
+	}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSyntheticsChain_shouldFail_missingValues(t *testing.T) {
+	// when
+	variables, failures := ExtractVariables([]ExtractorModel{
+		{Name: "token", JsonPath: "$.token"},
+		{Name: "session", Header: "X-Session"},
+		{Name: "csrf", Regex: "csrf=[a-z]+"},
+	}, SyntheticsResponse{Payload: "not json"})
+
+	// then
+	if len(variables) != 0 {
+		t.Errorf("Expected no variables, got %v", variables)
+	}
+	if diff := deep.Equal(failures, []string{
+		"extract token: $.token is missing",
+		"extract session: header X-Session is missing",
+		`extract csrf: payload does not match "csrf=[a-z]+"`,
+	}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSyntheticsChain_shouldbeError_invalidChain(t *testing.T) {
+	cases := []struct {
+		replaced string
+		by       string
+		expected string
+	}{
+		{"value: Bearer {{token}}", "value: Bearer {{password}}", "http monitor orders-api: requests[1]: variable password is not extracted by a previous request"},
+		{"    - name: token\n", "    - name: 1token\n", `requests[0]: extract[0]: name: expected a letter or underscore`},
+		{"      header: X-Session\n", "      header: X-Session\n      regex: abc\n", "requests[0]: extract[1]: expected exactly one of jsonPath, header or regex"},
+		{"      jsonPath: $.auth.token", "      jsonPath: auth.token", "requests[0]: extract[0]: jsonPath"},
+		{"regex: csrf=([a-z]+)", "regex: csrf=(", "requests[0]: extract[2]: regex"},
+		{"    path: /login\n", "    path: /login/{{csrf}}\n", "requests[0]: variable csrf is not extracted by a previous request"},
+	}
+
+	for _, c := range cases {
+		// when
+		yamlSpec := strings.ReplaceAll(syntheticsChainYamlSpec, "{{url}}", "https://orders.example.com")
+		err := (&OpenSloDataSource{}).GetOpenSloData(strings.Replace(yamlSpec, c.replaced, c.by, 1), &diag.Diagnostics{})
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestSyntheticsChain_shouldRender_chainedRequests(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.ReplaceAll(syntheticsChainYamlSpec, "{{url}}", "https://orders.example.com"), &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and k6 keeps the values in vars
+	script := openslo.Extension_k6_scripts["orders-api"]
+	for _, expected := range []string{
+		"  const vars = {};\n",
+		`    vars["token"] = jsonValue(res.json("auth.token"));`,
+		`    vars["session"] = res.headers["X-Session"];`,
+		`    vars["csrf"] = (res.body.match(new RegExp("csrf=([a-z]+)")) || [])[1];`,
+		`http.request("GET", "https://orders.example.com/orders/" + encodeURIComponent(vars["csrf"]), null, {"headers":{"Authorization":"Bearer " + vars["token"],"X-Session":vars["session"]},"timeout":"10s"});`,
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected the k6 script to contain %s, got %s", expected, script)
+		}
+	}
+
+	// and dynatrace sets the values in post-processing
+	var monitor DynatraceHttpMonitor
+	if err := json.Unmarshal([]byte(openslo.Extension_dynatrace_http_monitors["orders-api"]), &monitor); err != nil {
+		t.Fatal(err)
+	}
+	login, orders := monitor.Script.Requests[0], monitor.Script.Requests[1]
+	if diff := deep.Equal(login.PostProcessingScript, `function jsonValue(value) {
+  return typeof value === "string" ? value : JSON.stringify(value);
+}
+var body = JSON.parse(response.getResponseBody());
+api.setValue("token", jsonValue(body["auth"]["token"]));
+api.setValue("session", response.getHeaders().get("X-Session"));
+api.setValue("csrf", (response.getResponseBody().match(new RegExp("csrf=([a-z]+)")) || [])[1]);`); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal([]interface{}{orders.Url, orders.Configuration.RequestHeaders}, []interface{}{
+		"https://orders.example.com/orders/{csrf}",
+		[]DynatraceHttpHeader{{Name: "Authorization", Value: "Bearer {token}"}, {Name: "X-Session", Value: "{session}"}},
+	}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and blackbox skips the chained request
+	warnings := warningsWithSummary(diagnostics, "Cannot render blackbox request, skipping")
+	if len(warnings) != 1 || strings.Contains(openslo.Extension_blackbox_exporter_config, "openslo_orders_api_orders") {
+		t.Errorf("Expected the orders request to be skipped, got %v", warnings)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
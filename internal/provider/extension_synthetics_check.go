@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	SYNTHETICS_CHECK_DEFAULT_TIMEOUT = "10s"
+	// Response bodies are only read up to this size for the payload rules
+	SYNTHETICS_CHECK_MAX_BODY_BYTES = 1 << 20
+)
+
+// SyntheticsCheckResultModel is the result of a request of an HTTP monitor
+type SyntheticsCheckResultModel struct {
+	Monitor    string   `tfsdk:"monitor"`
+	Request    string   `tfsdk:"request"`
+	Method     string   `tfsdk:"method"`
+	Url        string   `tfsdk:"url"`
+	StatusCode int64    `tfsdk:"status_code"`
+	DurationMs int64    `tfsdk:"duration_ms"`
+	Passed     bool     `tfsdk:"passed"`
+	Failures   []string `tfsdk:"failures"`
+	// Values extracted for the next requests, kept out of the state as they often are credentials
+	Variables map[string]string `tfsdk:"-"`
+}
+
+// SyntheticsChecker runs the requests of HTTP monitors and checks the responses against their expected response
+type SyntheticsChecker struct {
+	Client *http.Client
+}
+
+func NewSyntheticsChecker(timeout time.Duration) *SyntheticsChecker {
+	return &SyntheticsChecker{Client: &http.Client{Timeout: timeout}}
+}
+
+// CheckHttpMonitors runs the requests of every enabled HTTP monitor in order, keyed by monitor/request. Variables
+// extracted by a request are substituted in the next ones, results keep the url with the {{name}} references, as the
+// variables are kept out of the state. Each attempt is bounded by the monitor timeout, a failed request is retried up
+// to the monitor retries.
+func (c *SyntheticsChecker) CheckHttpMonitors(ctx context.Context, monitors map[string]HTTPMonitorModel) map[string]SyntheticsCheckResultModel {
+	results := map[string]SyntheticsCheckResultModel{}
+	for _, monitorName := range sortedKeys(monitors) {
+		monitor := monitors[monitorName]
+		if !monitorEnabled(monitor.Enabled) {
+			continue
+		}
+		variables := map[string]string{}
+		for i, request := range monitor.Requests {
+			substituted := SubstituteVariables(request, variables)
+			result := c.checkRequestWithRetries(ctx, monitorName, monitor, i, substituted)
+			if substituted.Path != request.Path {
+				result.Url = RequestUrl(monitor.Url, request.Path)
+				for j, failure := range result.Failures {
+					result.Failures[j] = strings.ReplaceAll(failure, RequestUrl(monitor.Url, substituted.Path), result.Url)
+				}
+			}
+			for name, value := range result.Variables {
+				variables[name] = value
+			}
+			results[fmt.Sprintf("%s/%s", monitorName, result.Request)] = result
+		}
+	}
+	return results
+}
+
+func (c *SyntheticsChecker) checkRequestWithRetries(ctx context.Context, monitorName string, monitor HTTPMonitorModel, index int, request RequestModel) SyntheticsCheckResultModel {
+	timeout := monitorDuration(monitor.Timeout, MONITOR_DEFAULT_TIMEOUT)
+	var result SyntheticsCheckResultModel
+	for attempt := int64(0); attempt <= monitor.Retries; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		result = c.CheckRequest(attemptCtx, monitorName, monitor.Url, index, request)
+		cancel()
+		if result.Passed {
+			break
+		}
+	}
+	return result
+}
+
+// CheckRequest sends the request to the monitor url and path, with its method, headers, auth and body. The request
+// fails on a transport error or a failed rule of the expected response. Unnamed requests are named by their index.
+func (c *SyntheticsChecker) CheckRequest(ctx context.Context, monitorName string, baseUrl string, index int, request RequestModel) SyntheticsCheckResultModel {
+	method := strings.ToUpper(string(request.Method))
+	if method == "" {
+		method = string(GET)
+	}
+	name := RequestName(index, request)
+	result := SyntheticsCheckResultModel{
+		Monitor:  monitorName,
+		Request:  name,
+		Method:   method,
+		Url:      RequestUrl(baseUrl, request.Path),
+		Failures: []string{},
+	}
+
+	if err := ValidateResponseModel(request.ExpectedResponse); err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+
+	var body io.Reader
+	if request.Body != "" {
+		body = strings.NewReader(request.Body)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, result.Url, body)
+	if err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+	for _, header := range request.Headers {
+		httpRequest.Header.Add(header.Name, header.Value)
+	}
+	if err := c.authenticate(ctx, httpRequest, request.Auth); err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+
+	start := time.Now()
+	response, err := c.Client.Do(httpRequest)
+	if err != nil {
+		result.DurationMs = time.Since(start).Milliseconds()
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+	defer response.Body.Close()
+	payload, err := io.ReadAll(io.LimitReader(response.Body, SYNTHETICS_CHECK_MAX_BODY_BYTES))
+	latency := time.Since(start)
+	result.DurationMs = latency.Milliseconds()
+	result.StatusCode = int64(response.StatusCode)
+	if err != nil {
+		result.Failures = append(result.Failures, fmt.Sprintf("reading response body: %s", err.Error()))
+	}
+
+	checked := SyntheticsResponse{
+		StatusCode: response.StatusCode,
+		Headers:    response.Header,
+		Payload:    string(payload),
+		Latency:    latency,
+		Now:        time.Now(),
+	}
+	if response.TLS != nil && len(response.TLS.PeerCertificates) > 0 {
+		checked.CertificateNotAfter = &response.TLS.PeerCertificates[0].NotAfter
+	}
+	result.Failures = append(result.Failures, CheckResponse(request.ExpectedResponse, checked)...)
+	if len(request.Extract) > 0 {
+		variables, failures := ExtractVariables(request.Extract, checked)
+		result.Variables = variables
+		result.Failures = append(result.Failures, failures...)
+	}
+	result.Passed = len(result.Failures) == 0
+	return result
+}
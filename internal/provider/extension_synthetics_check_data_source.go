@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewSyntheticsCheckDataSource() datasource.DataSource {
+	return &SyntheticsCheckDataSource{}
+}
+
+// SyntheticsCheckDataSource runs the HTTP monitors of the synthetics extension, to catch broken monitors before shipping them
+type SyntheticsCheckDataSource struct {
+	Checker       *SyntheticsChecker                    `tfsdk:"-"`
+	Yaml_input    types.String                          `tfsdk:"yaml_input"`
+	Timeout       types.String                          `tfsdk:"timeout"`
+	Fail_on_error types.Bool                            `tfsdk:"fail_on_error"`
+	Results       map[string]SyntheticsCheckResultModel `tfsdk:"results"`
+	Passed        bool                                  `tfsdk:"passed"`
+}
+
+func (d *SyntheticsCheckDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_synthetics_check"
+}
+
+func (d *SyntheticsCheckDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs every request of the synthetics extension HTTP monitors against its url and path, and checks the response against the expected response codes and payload rules",
+		Attributes: map[string]schema.Attribute{
+			"yaml_input": schema.StringAttribute{
+				MarkdownDescription: "OpenSLO yaml content input, with openslo_synthetics/v1 HTTPMonitor documents",
+				Required:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of each request, as a Go duration. Defaults to " + SYNTHETICS_CHECK_DEFAULT_TIMEOUT,
+				Optional:            true,
+			},
+			"fail_on_error": schema.BoolAttribute{
+				MarkdownDescription: "Report failed checks as errors instead of warnings",
+				Optional:            true,
+			},
+			"results": schema.MapAttribute{
+				MarkdownDescription: "Check results keyed by monitor/request, unnamed requests are named by their index. Request names must be unique in a monitor",
+				Computed:            true,
+				ElementType:         SyntheticsCheckResultSchema,
+			},
+			"passed": schema.BoolAttribute{
+				MarkdownDescription: "True if every check passed",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SyntheticsCheckDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+}
+
+func (d *SyntheticsCheckDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var readData SyntheticsCheckDataSource
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &readData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.Timeout = readData.Timeout
+	d.Fail_on_error = readData.Fail_on_error
+	err := d.CheckSynthetics(ctx, readData.Yaml_input.ValueString(), &resp.Diagnostics)
+	if err != nil {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &d)...)
+}
+
+// CheckSynthetics runs the HTTP monitors of the yaml input. Failed checks are reported as warnings, or as errors with
+// fail_on_error. An error is only returned when the input cannot be read.
+func (d *SyntheticsCheckDataSource) CheckSynthetics(ctx context.Context, yamlInput string, diagnostics *diag.Diagnostics) error {
+	d.Yaml_input = types.StringValue(yamlInput)
+
+	timeout, err := time.ParseDuration(SYNTHETICS_CHECK_DEFAULT_TIMEOUT)
+	if !d.Timeout.IsNull() && !d.Timeout.IsUnknown() {
+		timeout, err = time.ParseDuration(d.Timeout.ValueString())
+	}
+	if err != nil {
+		diagnostics.AddError("Invalid Timeout", err.Error())
+		return err
+	}
+	if d.Checker == nil {
+		d.Checker = NewSyntheticsChecker(timeout)
+	}
+
+	// Rendering warnings are reported by the openslo data source
+	openslo := OpenSloDataSource{}
+	openSloDiagnostics := diag.Diagnostics{}
+	err = openslo.GetOpenSloData(yamlInput, &openSloDiagnostics)
+	diagnostics.Append(openSloDiagnostics.Errors()...)
+	if err != nil {
+		return err
+	}
+	err = openslo.ResolveAuthSecretReferences()
+	if err != nil {
+		diagnostics.AddError("Synthetics Secret Reference Error", err.Error())
+		return err
+	}
+
+	d.Results = d.Checker.CheckHttpMonitors(ctx, openslo.Extension_httpmonitor)
+	d.Passed = true
+	for _, key := range sortedKeys(d.Results) {
+		result := d.Results[key]
+		if result.Passed {
+			continue
+		}
+		d.Passed = false
+		detail := fmt.Sprintf("%s %s %s: %s", key, result.Method, result.Url, strings.Join(result.Failures, ", "))
+		if d.Fail_on_error.ValueBool() {
+			diagnostics.AddError("Synthetics check failed", detail)
+		} else {
+			diagnostics.AddWarning("Synthetics check failed", detail)
+		}
+	}
+	return nil
+}
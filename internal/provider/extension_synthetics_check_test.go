@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const syntheticsCheckYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: checkout-api
+spec:
+  url: {{url}}/
+  requests:
+  - name: login
+    method: POST
+    path: /login
+    body: '{"user": "synthetic"}'
+    headers:
+    - name: Content-Type
+      value: application/json
+    expectedResponse:
+      code:
+      - 200
+      - 201
+      payloadContains: token
+  - path: health
+    expectedResponse:
+      payloadNotContains: degraded
+  - name: missing
+    path: /missing
+`
+
+// syntheticsCheckServer answers the login with a token when called as expected, and reports a degraded health
+func syntheticsCheckServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || string(body) != `{"user": "synthetic"}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"token": "abc"}`))
+		case "/health":
+			_, _ = w.Write([]byte("status: degraded"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSyntheticsCheck_shouldReport_passAndFail(t *testing.T) {
+	// given
+	server := syntheticsCheckServer()
+	defer server.Close()
+	yamlSpec := strings.ReplaceAll(syntheticsCheckYamlSpec, "{{url}}", server.URL)
+
+	// when
+	diagnostics := diag.Diagnostics{}
+	check := SyntheticsCheckDataSource{}
+	err := check.CheckSynthetics(context.Background(), yamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Passed {
+		t.Error("Expected the check to fail")
+	}
+
+	// and
+	passed := map[string]bool{}
+	failures := map[string][]string{}
+	for key, result := range check.Results {
+		passed[key] = result.Passed
+		failures[key] = result.Failures
+	}
+	if diff := deep.Equal(passed, map[string]bool{
+		"checkout-api/login":   true,
+		"checkout-api/1":       false,
+		"checkout-api/missing": false,
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(failures, map[string][]string{
+		"checkout-api/login":   {},
+		"checkout-api/1":       {`payload contains "degraded"`},
+		"checkout-api/missing": {"status code 404, expected a non error status"},
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if result := check.Results["checkout-api/login"]; result.StatusCode != 201 || result.Url != server.URL+"/login" || result.Method != "POST" {
+		t.Errorf("Unexpected login result %+v", result)
+	}
+
+	// and failed checks are warnings
+	if len(diagnostics.Errors()) != 0 || len(diagnostics.Warnings()) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", diagnostics)
+	}
+
+	// and state can be serialized
+	ctx := context.Background()
+	schemaResp := datasource.SchemaResponse{}
+	check.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := state.Set(ctx, &check); diags.HasError() {
+		t.Fatal(diags)
+	}
+}
+
+func TestSyntheticsCheck_shouldbeError_failOnError(t *testing.T) {
+	// given
+	server := syntheticsCheckServer()
+	defer server.Close()
+	yamlSpec := strings.ReplaceAll(syntheticsCheckYamlSpec, "{{url}}", server.URL)
+
+	// when
+	diagnostics := diag.Diagnostics{}
+	check := SyntheticsCheckDataSource{Fail_on_error: types.BoolValue(true)}
+	err := check.CheckSynthetics(context.Background(), yamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics.Errors()) != 2 {
+		t.Errorf("Expected 2 errors, got %v", diagnostics)
+	}
+}
+
+func TestSyntheticsCheck_shouldFail_unreachable(t *testing.T) {
+	// given a closed server
+	server := syntheticsCheckServer()
+	server.Close()
+
+	// when
+	result := NewSyntheticsChecker(time.Second).CheckRequest(context.Background(), "monitor", server.URL, 0, RequestModel{})
+
+	// then
+	if result.Passed || len(result.Failures) != 1 || result.Request != "0" || result.Method != "GET" {
+		t.Errorf("Expected a transport failure, got %+v", result)
+	}
+}
+
+func TestSyntheticsCheck_shouldbeError_duplicateRequestNames(t *testing.T) {
+	cases := []struct {
+		replaced string
+		by       string
+		expected string
+	}{
+		{"  - name: missing\n", "  - name: login\n", "http monitor checkout-api: requests[2]: name login is already used by requests[0]"},
+		{"  - name: missing\n", "  - name: \"1\"\n", "http monitor checkout-api: requests[2]: name 1 is already used by requests[1]"},
+	}
+
+	for _, c := range cases {
+		// when
+		yamlSpec := strings.ReplaceAll(syntheticsCheckYamlSpec, "{{url}}", "https://checkout.example.com")
+		err := (&SyntheticsCheckDataSource{}).CheckSynthetics(context.Background(), strings.Replace(yamlSpec, c.replaced, c.by, 1), &diag.Diagnostics{})
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
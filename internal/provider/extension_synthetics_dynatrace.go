@@ -0,0 +1,301 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	DYNATRACE_SCRIPT_VERSION             = "1.0"
+	DYNATRACE_MONITOR_TYPE_HTTP          = "HTTP"
+	DYNATRACE_RULE_HTTP_STATUSES         = "httpStatusesList"
+	DYNATRACE_RULE_PATTERN               = "patternConstraint"
+	DYNATRACE_RULE_REGEX                 = "regexConstraint"
+	DYNATRACE_RULE_CERTIFICATE_EXPIRY    = "certificateExpiryDateConstraint"
+	DYNATRACE_HTTP_AVAILABILITY_METRIC   = "builtin:synthetic.http.availability.location.total:splitBy()"
+	DYNATRACE_HTTP_CHECK_ENTITY_TYPE     = "HTTP_CHECK"
+	DYNATRACE_SLO_EVALUATION_TYPE        = "AGGREGATE"
+	DYNATRACE_TAG_SERVICE                = "openslo-service"
+	DYNATRACE_TAG_MONITOR                = "openslo"
+	DYNATRACE_DEFAULT_FAILED_STATUS_CODE = ">=400"
+)
+
+// DynatraceHttpMonitor is a Dynatrace synthetic HTTP monitor, as in the synthetic monitors API
+type DynatraceHttpMonitor struct {
+	Name         string                     `json:"name"`
+	Type         string                     `json:"type"`
+	FrequencyMin int                        `json:"frequencyMin"`
+	Enabled      bool                       `json:"enabled"`
+	Locations    []string                   `json:"locations"`
+	Script       DynatraceHttpMonitorScript `json:"script"`
+	Tags         []string                   `json:"tags"`
+}
+
+type DynatraceHttpMonitorScript struct {
+	Version  string                 `json:"version"`
+	Requests []DynatraceHttpRequest `json:"requests"`
+}
+
+type DynatraceHttpRequest struct {
+	Description          string                            `json:"description"`
+	Url                  string                            `json:"url"`
+	Method               string                            `json:"method"`
+	RequestBody          string                            `json:"requestBody,omitempty"`
+	Configuration        DynatraceHttpRequestConfiguration `json:"configuration"`
+	Validation           DynatraceHttpRequestValidation    `json:"validation"`
+	PostProcessingScript string                            `json:"postProcessingScript,omitempty"`
+}
+
+type DynatraceHttpRequestConfiguration struct {
+	AcceptAnyCertificate bool                  `json:"acceptAnyCertificate"`
+	FollowRedirects      bool                  `json:"followRedirects"`
+	RequestHeaders       []DynatraceHttpHeader `json:"requestHeaders,omitempty"`
+}
+
+type DynatraceHttpHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type DynatraceHttpRequestValidation struct {
+	Rules         []DynatraceValidationRule `json:"rules"`
+	RulesChaining string                    `json:"rulesChaining"`
+}
+
+type DynatraceValidationRule struct {
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	PassIfFound bool   `json:"passIfFound"`
+}
+
+// DynatraceSlo is a Dynatrace SLO, as in the service-level objectives API
+type DynatraceSlo struct {
+	Name             string  `json:"name"`
+	Description      string  `json:"description,omitempty"`
+	Enabled          bool    `json:"enabled"`
+	EvaluationType   string  `json:"evaluationType"`
+	Filter           string  `json:"filter"`
+	MetricExpression string  `json:"metricExpression"`
+	Target           float64 `json:"target"`
+	Warning          float64 `json:"warning"`
+	Timeframe        string  `json:"timeframe"`
+}
+
+// SyntheticsExtensionRenderDynatrace renders the Dynatrace HTTP monitor of every HTTP monitor, and a Dynatrace SLO
+// on the synthetic availability for every objective of the SLOs of a service that has HTTP monitors. Objectives with
+// an indicator referencing a monitor are measured on that monitor only. Request auth produces a warning, Dynatrace
+// monitors read credentials from its credential vault.
+func (d *OpenSloDataSource) SyntheticsExtensionRenderDynatrace(diagnostics *diag.Diagnostics) error {
+	d.Extension_dynatrace_http_monitors = map[string]string{}
+	d.Extension_dynatrace_slos = map[string]string{}
+
+	monitorsByService := map[string][]string{}
+	for _, monitorName := range sortedKeys(d.Extension_httpmonitor) {
+		monitor := d.Extension_httpmonitor[monitorName]
+		for i, request := range monitor.Requests {
+			if request.Auth.Type == "" {
+				continue
+			}
+			requestName := RequestName(i, request)
+			diagnostics.AddWarning("Cannot render Dynatrace auth, skipping",
+				fmt.Sprintf("http monitor %s request %s: %s auth is not supported, set the authentication of the monitor with a Dynatrace credential vault entry", monitorName, requestName, request.Auth.Type))
+		}
+		monitorJson, err := json.MarshalIndent(DynatraceHttpMonitorOf(monitorName, monitor), "", "  ")
+		if err != nil {
+			return fmt.Errorf("http monitor %s: %w", monitorName, err)
+		}
+		d.Extension_dynatrace_http_monitors[monitorName] = string(monitorJson)
+		if monitor.ServiceRef != "" {
+			monitorsByService[monitor.ServiceRef] = append(monitorsByService[monitor.ServiceRef], dynatraceMonitorName(monitorName, monitor))
+		}
+	}
+
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			var monitorNames []string
+			if slo.ServiceRef != "" {
+				monitorNames = monitorsByService[slo.ServiceRef]
+			}
+			if monitorRef := ObjectiveIndicator(slo, slo.Objectives[i]).SyntheticMonitorRef; monitorRef != "" {
+				monitorNames = []string{dynatraceMonitorName(monitorRef, d.Extension_httpmonitor[monitorRef])}
+			}
+			if len(monitorNames) == 0 {
+				continue
+			}
+			sloJson, err := json.MarshalIndent(DynatraceSloOf(sloName, key, slo, slo.Objectives[i], monitorNames), "", "  ")
+			if err != nil {
+				return fmt.Errorf("objective %s: %w", key, err)
+			}
+			d.Extension_dynatrace_slos[key] = string(sloJson)
+		}
+	}
+	return nil
+}
+
+// DynatraceHttpMonitorOf returns the Dynatrace HTTP monitor running the requests of the monitor, in order, on the
+// schedule and locations of the monitor
+func DynatraceHttpMonitorOf(monitorName string, monitor HTTPMonitorModel) DynatraceHttpMonitor {
+	tags := []string{DYNATRACE_TAG_MONITOR}
+	if monitor.ServiceRef != "" {
+		tags = append(tags, fmt.Sprintf("%s:%s", DYNATRACE_TAG_SERVICE, monitor.ServiceRef))
+	}
+	requests := []DynatraceHttpRequest{}
+	for _, request := range monitor.Requests {
+		requests = append(requests, dynatraceHttpRequest(monitor.Url, request))
+	}
+	return DynatraceHttpMonitor{
+		Name:         dynatraceMonitorName(monitorName, monitor),
+		Type:         DYNATRACE_MONITOR_TYPE_HTTP,
+		FrequencyMin: monitorFrequencyMinutes(monitor.Frequency),
+		Enabled:      monitorEnabled(monitor.Enabled),
+		Locations:    append([]string{}, monitor.Locations...),
+		Script: DynatraceHttpMonitorScript{
+			Version:  DYNATRACE_SCRIPT_VERSION,
+			Requests: requests,
+		},
+		Tags: tags,
+	}
+}
+
+func dynatraceHttpRequest(baseUrl string, request RequestModel) DynatraceHttpRequest {
+	method := strings.ToUpper(string(request.Method))
+	if method == "" {
+		method = string(GET)
+	}
+	description := request.Description
+	if description == "" {
+		description = request.Name
+	}
+	var headers []DynatraceHttpHeader
+	for _, header := range request.Headers {
+		headers = append(headers, DynatraceHttpHeader{Name: header.Name, Value: dynatraceVariables(header.Value)})
+	}
+	return DynatraceHttpRequest{
+		Description: description,
+		Url:         dynatraceVariables(RequestUrl(baseUrl, request.Path)),
+		Method:      method,
+		RequestBody: dynatraceVariables(request.Body),
+		Configuration: DynatraceHttpRequestConfiguration{
+			FollowRedirects: true,
+			RequestHeaders:  headers,
+		},
+		Validation:           dynatraceValidation(request.ExpectedResponse),
+		PostProcessingScript: dynatracePostProcessing(request),
+	}
+}
+
+// dynatraceVariables replaces the {{name}} variable references by the {name} Dynatrace syntax
+func dynatraceVariables(value string) string {
+	return variableReferenceRegex.ReplaceAllString(value, "{$1}")
+}
+
+// dynatracePostProcessing returns the post-processing script storing the extracted values with api.setValue for the
+// next requests, followed by the dtPostProcessing script of the expected response
+func dynatracePostProcessing(request RequestModel) string {
+	var lines []string
+	for _, extractor := range request.Extract {
+		if extractor.JsonPath != "" {
+			lines = append(lines,
+				"function jsonValue(value) {\n  return typeof value === \"string\" ? value : JSON.stringify(value);\n}",
+				"var body = JSON.parse(response.getResponseBody());")
+			break
+		}
+	}
+	for _, extractor := range request.Extract {
+		var value string
+		switch {
+		case extractor.JsonPath != "":
+			segments, _ := ParseJsonPath(extractor.JsonPath)
+			value = fmt.Sprintf("jsonValue(body%s)", jsonPathAccessor(segments))
+		case extractor.Header != "":
+			value = fmt.Sprintf("response.getHeaders().get(%s)", jsLiteral(extractor.Header))
+		case extractor.Regex != "":
+			value = fmt.Sprintf("(response.getResponseBody().match(new RegExp(%s)) || [])[%d]", jsLiteral(extractor.Regex), extractorRegexGroup(extractor.Regex))
+		}
+		lines = append(lines, fmt.Sprintf("api.setValue(%s, %s);", jsLiteral(extractor.Name), value))
+	}
+	if request.ExpectedResponse.DynatracePostProcessing != "" {
+		lines = append(lines, request.ExpectedResponse.DynatracePostProcessing)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dynatraceValidation returns the validation rules of the expected response, failing on any error status when no code is expected.
+// Code ranges, header, JSON path and latency assertions have no Dynatrace rule, they are only run by openslo_synthetics_check.
+func dynatraceValidation(response ResponseModel) DynatraceHttpRequestValidation {
+	rules := []DynatraceValidationRule{}
+	if len(response.Codes) > 0 {
+		codes := make([]string, len(response.Codes))
+		for i, code := range response.Codes {
+			codes[i] = strconv.Itoa(code)
+		}
+		rules = append(rules, DynatraceValidationRule{Type: DYNATRACE_RULE_HTTP_STATUSES, Value: strings.Join(codes, ","), PassIfFound: true})
+	} else {
+		rules = append(rules, DynatraceValidationRule{Type: DYNATRACE_RULE_HTTP_STATUSES, Value: DYNATRACE_DEFAULT_FAILED_STATUS_CODE, PassIfFound: false})
+	}
+	if response.PayloadContains != "" {
+		rules = append(rules, DynatraceValidationRule{Type: DYNATRACE_RULE_PATTERN, Value: response.PayloadContains, PassIfFound: true})
+	}
+	if response.PayloadNotContains != "" {
+		rules = append(rules, DynatraceValidationRule{Type: DYNATRACE_RULE_PATTERN, Value: response.PayloadNotContains, PassIfFound: false})
+	}
+	if response.PayloadMatches != "" {
+		rules = append(rules, DynatraceValidationRule{Type: DYNATRACE_RULE_REGEX, Value: response.PayloadMatches, PassIfFound: true})
+	}
+	if minValidity, err := ParseOpenSloDuration(response.MinCertificateValidity); err == nil {
+		// Fails when the certificate expires in less days
+		days := int64((minValidity + DAY - 1) / DAY)
+		rules = append(rules, DynatraceValidationRule{Type: DYNATRACE_RULE_CERTIFICATE_EXPIRY, Value: strconv.FormatInt(days, 10), PassIfFound: false})
+	}
+	return DynatraceHttpRequestValidation{Rules: rules, RulesChaining: "or"}
+}
+
+// DynatraceSloOf returns a Dynatrace SLO of the objective, on the synthetic availability of the monitors
+func DynatraceSloOf(sloName string, key string, slo SLOModel, objective ObjectiveModel, monitorNames []string) DynatraceSlo {
+	name := slo.Metadata.DisplayName
+	if name == "" {
+		name = sloName
+	}
+	if len(slo.Objectives) > 1 {
+		name = fmt.Sprintf("%s - %s", name, strings.TrimPrefix(key, sloName+"/"))
+	}
+	quoted := make([]string, len(monitorNames))
+	for i, monitorName := range monitorNames {
+		quoted[i] = strconv.Quote(monitorName)
+	}
+	sort.Strings(quoted)
+	target := roundFloat(ObjectiveTarget(objective) * 100)
+	return DynatraceSlo{
+		Name:             name,
+		Description:      slo.Description,
+		Enabled:          true,
+		EvaluationType:   DYNATRACE_SLO_EVALUATION_TYPE,
+		Filter:           fmt.Sprintf("type(%q),entityName.in(%s)", DYNATRACE_HTTP_CHECK_ENTITY_TYPE, strings.Join(quoted, ",")),
+		MetricExpression: DYNATRACE_HTTP_AVAILABILITY_METRIC,
+		Target:           target,
+		// Warn when half of the error budget is consumed
+		Warning:   roundFloat(target + (100-target)/2),
+		Timeframe: "-" + SloTimeWindow(slo),
+	}
+}
+
+// RequestUrl joins the monitor url and the request path
+func RequestUrl(baseUrl string, path string) string {
+	if path == "" {
+		return baseUrl
+	}
+	return strings.TrimSuffix(baseUrl, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func dynatraceMonitorName(monitorName string, monitor HTTPMonitorModel) string {
+	if monitor.Metadata.DisplayName != "" {
+		return monitor.Metadata.DisplayName
+	}
+	return monitorName
+}
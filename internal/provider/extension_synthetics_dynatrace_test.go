@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const dynatraceYamlSpec = `
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+spec:
+  description: Checkout service
+---
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: checkout-api
+  displayName: Checkout API
+spec:
+  url: https://checkout.example.com/
+  serviceRef: checkout
+  requests:
+  - name: login
+    description: Log in
+    method: POST
+    path: /login
+    body: '{"user": "synthetic"}'
+    headers:
+    - name: Content-Type
+      value: application/json
+    expectedResponse:
+      code:
+      - 200
+      - 201
+      payloadContains: token
+      dynatrace_postprocessing: api.setValue("token", JSON.parse(response.getResponseBody()).token);
+  - name: health
+    path: health
+    expectedResponse:
+      payloadNotContains: degraded
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+  displayName: Checkout availability
+spec:
+  description: Checkout is up
+  service: checkout
+  indicator:
+    apiVersion: openslo/v1
+    kind: SLI
+    metadata:
+      name: synthetic
+    spec:
+      ratioMetric:
+        good:
+          metricSource:
+            type: dynatrace
+            spec:
+              query: builtin:synthetic.http.availability.location.total
+        total:
+          metricSource:
+            type: dynatrace
+            spec:
+              query: builtin:synthetic.http.availability.location.total
+  timeWindow:
+  - duration: 7d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: unmonitored
+spec:
+  indicator:
+    apiVersion: openslo/v1
+    kind: SLI
+    metadata:
+      name: other
+    spec:
+      thresholdMetric:
+        metricSource:
+          type: dynatrace
+          spec:
+            query: builtin:service.response.time
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+`
+
+func TestSyntheticsDynatrace_shouldbeValid_httpMonitor(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(dynatraceYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	var monitor DynatraceHttpMonitor
+	err = json.Unmarshal([]byte(openslo.Extension_dynatrace_http_monitors["checkout-api"]), &monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DynatraceHttpMonitor{
+		Name:         "Checkout API",
+		Type:         "HTTP",
+		FrequencyMin: 15,
+		Enabled:      true,
+		Locations:    []string{},
+		Script: DynatraceHttpMonitorScript{
+			Version: "1.0",
+			Requests: []DynatraceHttpRequest{
+				{
+					Description: "Log in",
+					Url:         "https://checkout.example.com/login",
+					Method:      "POST",
+					RequestBody: `{"user": "synthetic"}`,
+					Configuration: DynatraceHttpRequestConfiguration{
+						FollowRedirects: true,
+						RequestHeaders:  []DynatraceHttpHeader{{Name: "Content-Type", Value: "application/json"}},
+					},
+					Validation: DynatraceHttpRequestValidation{
+						Rules: []DynatraceValidationRule{
+							{Type: "httpStatusesList", Value: "200,201", PassIfFound: true},
+							{Type: "patternConstraint", Value: "token", PassIfFound: true},
+						},
+						RulesChaining: "or",
+					},
+					PostProcessingScript: `api.setValue("token", JSON.parse(response.getResponseBody()).token);`,
+				},
+				{
+					Description: "health",
+					Url:         "https://checkout.example.com/health",
+					Method:      "GET",
+					Configuration: DynatraceHttpRequestConfiguration{
+						FollowRedirects: true,
+					},
+					Validation: DynatraceHttpRequestValidation{
+						Rules: []DynatraceValidationRule{
+							{Type: "httpStatusesList", Value: ">=400", PassIfFound: false},
+							{Type: "patternConstraint", Value: "degraded", PassIfFound: false},
+						},
+						RulesChaining: "or",
+					},
+				},
+			},
+		},
+		Tags: []string{"openslo", "openslo-service:checkout"},
+	}
+	diff := deep.Equal(monitor, expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSyntheticsDynatrace_shouldbeValid_slo(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(dynatraceYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and only SLOs of monitored services are rendered
+	diff := deep.Equal(sortedKeys(openslo.Extension_dynatrace_slos), []string{"checkout-availability/0"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	var slo DynatraceSlo
+	err = json.Unmarshal([]byte(openslo.Extension_dynatrace_slos["checkout-availability/0"]), &slo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DynatraceSlo{
+		Name:             "Checkout availability",
+		Description:      "Checkout is up",
+		Enabled:          true,
+		EvaluationType:   "AGGREGATE",
+		Filter:           `type("HTTP_CHECK"),entityName.in("Checkout API")`,
+		MetricExpression: "builtin:synthetic.http.availability.location.total:splitBy()",
+		Target:           99,
+		Warning:          99.5,
+		Timeframe:        "-7d",
+	}
+	diff = deep.Equal(slo, expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSyntheticsDynatrace_shouldMap_regexAndCertificateRules(t *testing.T) {
+	// when
+	validation := dynatraceValidation(ResponseModel{PayloadMatches: `"status":\s*"ok"`, MinCertificateValidity: "2w"})
+
+	// then
+	diff := deep.Equal(validation.Rules, []DynatraceValidationRule{
+		{Type: "httpStatusesList", Value: ">=400", PassIfFound: false},
+		{Type: "regexConstraint", Value: `"status":\s*"ok"`, PassIfFound: true},
+		{Type: "certificateExpiryDateConstraint", Value: "14", PassIfFound: false},
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+}
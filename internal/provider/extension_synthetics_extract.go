@@ -20,6 +20,26 @@ func (d *OpenSloDataSource) ExtractSyntheticsExtensionDocument(doc *YamlSpec, de
 		err = decType.Decode(&typedDoc)
 		typedDoc.Spec.Metadata = doc.Metadata
 		d.Extension_browsermonitor[doc.Metadata.Name] = typedDoc.Spec
+	case "TCPMonitor":
+		var typedDoc YamlSpecTyped[TCPMonitorModel]
+		err = decType.Decode(&typedDoc)
+		typedDoc.Spec.Metadata = doc.Metadata
+		d.Extension_tcpmonitor[doc.Metadata.Name] = typedDoc.Spec
+	case "DNSMonitor":
+		var typedDoc YamlSpecTyped[DNSMonitorModel]
+		err = decType.Decode(&typedDoc)
+		typedDoc.Spec.Metadata = doc.Metadata
+		d.Extension_dnsmonitor[doc.Metadata.Name] = typedDoc.Spec
+	case "GRPCMonitor":
+		var typedDoc YamlSpecTyped[GRPCMonitorModel]
+		err = decType.Decode(&typedDoc)
+		typedDoc.Spec.Metadata = doc.Metadata
+		d.Extension_grpcmonitor[doc.Metadata.Name] = typedDoc.Spec
+	case "TLSMonitor":
+		var typedDoc YamlSpecTyped[TLSMonitorModel]
+		err = decType.Decode(&typedDoc)
+		typedDoc.Spec.Metadata = doc.Metadata
+		d.Extension_tlsmonitor[doc.Metadata.Name] = typedDoc.Spec
 	default:
 		err = errors.New("Unknown kind: " + doc.Kind)
 	}
@@ -29,23 +49,100 @@ func (d *OpenSloDataSource) ExtractSyntheticsExtensionDocument(doc *YamlSpec, de
 func (d *OpenSloDataSource) SyntheticsExtensionPostExtractionLogic() error {
 	for i := range d.Extension_httpmonitor {
 		synthetic := d.Extension_httpmonitor[i]
+		for j, request := range synthetic.Requests {
+			if err := ValidateResponseModel(request.ExpectedResponse); err != nil {
+				return fmt.Errorf("http monitor %s requests[%d]: %w", i, j, err)
+			}
+			auth, err := validateAuth(request.Auth)
+			if err != nil {
+				return fmt.Errorf("http monitor %s requests[%d]: auth: %w", i, j, err)
+			}
+			synthetic.Requests[j].Auth = auth
+		}
+		if err := ValidateRequestNames(synthetic.Requests); err != nil {
+			return fmt.Errorf("http monitor %s: %w", i, err)
+		}
+		if err := ValidateRequestChain(synthetic.Requests); err != nil {
+			return fmt.Errorf("http monitor %s: %w", i, err)
+		}
+		if err := normalizeSchedule(&synthetic.Frequency, &synthetic.Timeout, synthetic.Retries, &synthetic.Enabled); err != nil {
+			return fmt.Errorf("http monitor %s: %w", i, err)
+		}
 		if synthetic.ServiceRef != "" {
 			synthetic.Service = d.Services[d.Extension_httpmonitor[i].ServiceRef]
 			if synthetic.Service.Metadata.Name == "" {
 				return fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_http", synthetic.ServiceRef)
 			}
-			d.Extension_httpmonitor[i] = synthetic
 		}
+		d.Extension_httpmonitor[i] = synthetic
 	}
 	for i := range d.Extension_browsermonitor {
 		synthetic := d.Extension_browsermonitor[i]
+		if err := normalizeSchedule(&synthetic.Frequency, &synthetic.Timeout, synthetic.Retries, &synthetic.Enabled); err != nil {
+			return fmt.Errorf("browser monitor %s: %w", i, err)
+		}
+		if err := ValidateBrowserSteps(synthetic); err != nil {
+			return fmt.Errorf("browser monitor %s: %w", i, err)
+		}
 		if synthetic.ServiceRef != "" {
 			synthetic.Service = d.Services[d.Extension_browsermonitor[i].ServiceRef]
 			if synthetic.Service.Metadata.Name == "" {
 				return fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_browser", synthetic.ServiceRef)
 			}
-			d.Extension_browsermonitor[i] = synthetic
 		}
+		d.Extension_browsermonitor[i] = synthetic
+	}
+	for i := range d.Extension_tcpmonitor {
+		synthetic, err := normalizeTCPMonitor(d.Extension_tcpmonitor[i])
+		if err != nil {
+			return fmt.Errorf("tcp monitor %s: %w", i, err)
+		}
+		if synthetic.ServiceRef != "" {
+			synthetic.Service = d.Services[synthetic.ServiceRef]
+			if synthetic.Service.Metadata.Name == "" {
+				return fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_tcp", synthetic.ServiceRef)
+			}
+		}
+		d.Extension_tcpmonitor[i] = synthetic
+	}
+	for i := range d.Extension_dnsmonitor {
+		synthetic, err := normalizeDNSMonitor(d.Extension_dnsmonitor[i])
+		if err != nil {
+			return fmt.Errorf("dns monitor %s: %w", i, err)
+		}
+		if synthetic.ServiceRef != "" {
+			synthetic.Service = d.Services[synthetic.ServiceRef]
+			if synthetic.Service.Metadata.Name == "" {
+				return fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_dns", synthetic.ServiceRef)
+			}
+		}
+		d.Extension_dnsmonitor[i] = synthetic
+	}
+	for i := range d.Extension_grpcmonitor {
+		synthetic, err := normalizeGRPCMonitor(d.Extension_grpcmonitor[i])
+		if err != nil {
+			return fmt.Errorf("grpc monitor %s: %w", i, err)
+		}
+		if synthetic.ServiceRef != "" {
+			synthetic.Service = d.Services[synthetic.ServiceRef]
+			if synthetic.Service.Metadata.Name == "" {
+				return fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_grpc", synthetic.ServiceRef)
+			}
+		}
+		d.Extension_grpcmonitor[i] = synthetic
+	}
+	for i := range d.Extension_tlsmonitor {
+		synthetic, err := normalizeTLSMonitor(d.Extension_tlsmonitor[i])
+		if err != nil {
+			return fmt.Errorf("tls monitor %s: %w", i, err)
+		}
+		if synthetic.ServiceRef != "" {
+			synthetic.Service = d.Services[synthetic.ServiceRef]
+			if synthetic.Service.Metadata.Name == "" {
+				return fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_tls", synthetic.ServiceRef)
+			}
+		}
+		d.Extension_tlsmonitor[i] = synthetic
 	}
 	return nil
 }
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	// Synthetic indicators are measured from the blackbox_exporter probes scraped by prometheus. Pyrra reads the probes
+	// as a bool gauge, nobl9 has no agent to read them from, and the other backends do not read prometheus sources.
+	SYNTHETICS_INDICATOR_SOURCE_TYPE = "prometheus"
+	BLACKBOX_PROBE_SUCCESS_METRIC    = "probe_success"
+)
+
+// SyntheticsExtensionIndicatorLogic synthesizes the ratio metric of the SLIs referencing an HTTP monitor, named and
+// inline ones, before they are embedded in their SLOs
+func (d *OpenSloDataSource) SyntheticsExtensionIndicatorLogic() error {
+	for k := range d.Slis {
+		sli, err := d.syntheticIndicator(d.Slis[k])
+		if err != nil {
+			return fmt.Errorf("sli %s: %w", k, err)
+		}
+		d.Slis[k] = sli
+	}
+	for k := range d.Slos {
+		slo := d.Slos[k]
+		indicator, err := d.syntheticIndicator(slo.Indicator)
+		if err != nil {
+			return fmt.Errorf("slo %s indicator: %w", k, err)
+		}
+		slo.Indicator = indicator
+		for j := range slo.Objectives {
+			indicator, err := d.syntheticIndicator(slo.Objectives[j].Indicator)
+			if err != nil {
+				return fmt.Errorf("slo %s objectives[%d] indicator: %w", k, j, err)
+			}
+			slo.Objectives[j].Indicator = indicator
+		}
+		d.Slos[k] = slo
+	}
+	return nil
+}
+
+func (d *OpenSloDataSource) syntheticIndicator(sli SLIModel) (SLIModel, error) {
+	if sli.SyntheticMonitorRef == "" {
+		return sli, nil
+	}
+	if _, ok := d.Extension_httpmonitor[sli.SyntheticMonitorRef]; !ok {
+		return sli, fmt.Errorf("bad reference: No object of kind %s with name %s", "synthetics_http", sli.SyntheticMonitorRef)
+	}
+	combined := sli.RatioMetric.RawType != ""
+	for _, metricSource := range sli.metricSources() {
+		combined = combined || isMetricSourceSet(*metricSource)
+	}
+	if combined {
+		return sli, fmt.Errorf("syntheticMonitorRef cannot be combined with a thresholdMetric or a ratioMetric")
+	}
+	sli.RatioMetric = SyntheticRatioMetric(sli.SyntheticMonitorRef)
+	return sli, nil
+}
+
+// SyntheticRatioMetric returns the ratio of successful probes of an HTTP monitor over all its probes, as scraped
+// through the blackbox_exporter scrape configs
+func SyntheticRatioMetric(monitorName string) RatioMetricModel {
+	selector := SyntheticProbeSelector(monitorName)
+	return RatioMetricModel{
+		Good: MetricModel{MetricSource: MetricSource{
+			Type: SYNTHETICS_INDICATOR_SOURCE_TYPE,
+			Spec: FreeformMap{"query": fmt.Sprintf("sum(sum_over_time(%s[%s]))", selector, PROMETHEUS_WINDOW_PLACEHOLDER)},
+		}},
+		Total: MetricModel{MetricSource: MetricSource{
+			Type: SYNTHETICS_INDICATOR_SOURCE_TYPE,
+			Spec: FreeformMap{"query": fmt.Sprintf("sum(count_over_time(%s[%s]))", selector, PROMETHEUS_WINDOW_PLACEHOLDER)},
+		}},
+	}
+}
+
+// SyntheticProbeSelector returns the series selector of the probe_success series of an HTTP monitor
+func SyntheticProbeSelector(monitorName string) string {
+	return fmt.Sprintf("%s{%s=%q}", BLACKBOX_PROBE_SUCCESS_METRIC, PROMETHEUS_LABEL_MONITOR, monitorName)
+}
+
+// SyntheticsExtensionIndicatorWarnings warns about the HTTP monitors referenced by SLIs that have no probe_success
+// series: monitors left out by the selector or disabled have no scrape config, and requests using extracted variables
+// are not probed. It runs after the blackbox rendering.
+func (d *OpenSloDataSource) SyntheticsExtensionIndicatorWarnings(diagnostics *diag.Diagnostics) {
+	monitorNames := map[string]bool{}
+	for _, sli := range d.Slis {
+		if sli.SyntheticMonitorRef != "" {
+			monitorNames[sli.SyntheticMonitorRef] = true
+		}
+	}
+	for _, slo := range d.Slos {
+		for _, objective := range slo.Objectives {
+			if monitorRef := ObjectiveIndicator(slo, objective).SyntheticMonitorRef; monitorRef != "" {
+				monitorNames[monitorRef] = true
+			}
+		}
+	}
+	if len(monitorNames) == 0 {
+		return
+	}
+
+	if d.Extension_blackbox_exporter_address.ValueString() == "" {
+		diagnostics.AddWarning("Synthetic indicator may have no data",
+			fmt.Sprintf("extension_blackbox_exporter_address is not set, the scrape configs of the monitors referenced by syntheticMonitorRef probe through %s", BLACKBOX_DEFAULT_EXPORTER_ADDRESS))
+	}
+	for _, monitorName := range sortedKeys(monitorNames) {
+		monitor, ok := d.Extension_httpmonitor[monitorName]
+		if !ok {
+			diagnostics.AddWarning("Synthetic indicator has no data",
+				fmt.Sprintf("http monitor %s is not kept by the selector, it has no scrape config", monitorName))
+			continue
+		}
+		if !monitorEnabled(monitor.Enabled) {
+			diagnostics.AddWarning("Synthetic indicator has no data",
+				fmt.Sprintf("http monitor %s is disabled, it has no scrape config", monitorName))
+			continue
+		}
+		var skipped []string
+		for i, request := range monitor.Requests {
+			// See SyntheticsExtensionRenderBlackbox
+			if len(RequestVariableReferences(request)) > 0 {
+				requestName := RequestName(i, request)
+				skipped = append(skipped, requestName)
+			}
+		}
+		switch {
+		case len(skipped) == len(monitor.Requests):
+			diagnostics.AddWarning("Synthetic indicator has no data",
+				fmt.Sprintf("http monitor %s has no request probed by blackbox_exporter, they use variables extracted by previous requests", monitorName))
+		case len(skipped) > 0:
+			diagnostics.AddWarning("Synthetic indicator is partial",
+				fmt.Sprintf("http monitor %s requests %v use variables extracted by previous requests, they are not probed by blackbox_exporter and not measured", monitorName, skipped))
+		}
+	}
+}
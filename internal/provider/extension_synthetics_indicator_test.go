@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const syntheticsIndicatorYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: checkout-api
+  displayName: Checkout API
+spec:
+  url: https://checkout.example.com
+  requests:
+  - path: /health
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: checkout-api-success
+spec:
+  syntheticMonitorRef: checkout-api
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+spec:
+  indicatorRef: checkout-api-success
+  budgetingMethod: Occurrences
+  timeWindow:
+  - duration: 28d
+    isRolling: true
+  objectives:
+  - target: 0.99
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-inline
+spec:
+  indicator:
+    apiVersion: openslo/v1
+    kind: SLI
+    metadata:
+      name: checkout-inline-success
+    spec:
+      syntheticMonitorRef: checkout-api
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.95
+`
+
+func TestSyntheticsIndicator_shouldbeValid(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(syntheticsIndicatorYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := RatioMetricModel{
+		Good:  MetricModel{MetricSource: MetricSource{Type: "prometheus", Spec: FreeformMap{"query": `sum(sum_over_time(probe_success{openslo_monitor="checkout-api"}[{{.window}}]))`}}},
+		Total: MetricModel{MetricSource: MetricSource{Type: "prometheus", Spec: FreeformMap{"query": `sum(count_over_time(probe_success{openslo_monitor="checkout-api"}[{{.window}}]))`}}},
+	}
+	if diff := deep.Equal(openslo.Slis["checkout-api-success"].RatioMetric, expected); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(openslo.Slos["checkout-availability"].Indicator.RatioMetric, expected); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(openslo.Slos["checkout-inline"].Indicator.RatioMetric, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the SLOs are rendered as prometheus rules
+	if !strings.Contains(openslo.Prometheus_rules["checkout-availability"], `sum(sum_over_time(probe_success{openslo_monitor="checkout-api"}[5m]))`) {
+		t.Errorf("Expected the prometheus rules to use probe_success, got %s", openslo.Prometheus_rules["checkout-availability"])
+	}
+
+	// and pyrra reads the probes as a bool gauge
+	if !strings.Contains(openslo.Pyrra_manifests["checkout-availability/0"], "bool_gauge:\n      metric: probe_success{openslo_monitor=\"checkout-api\"}") {
+		t.Errorf("Expected a pyrra bool gauge indicator, got %s", openslo.Pyrra_manifests["checkout-availability/0"])
+	}
+
+	// and nobl9 rejects the synthetic indicator
+	slo := openslo.Slos["checkout-availability"]
+	slo.ServiceRef = "checkout"
+	if _, err := Nobl9Slo("checkout-availability", NOBL9_DEFAULT_PROJECT, slo, nil); err == nil || !strings.Contains(err.Error(), "spec.indicator.syntheticMonitorRef is not supported by nobl9") {
+		t.Errorf("Expected nobl9 to reject the synthetic indicator, got %v", err)
+	}
+
+	// and the dynatrace SLO is measured on the monitor, without a service
+	var dynatraceSlo DynatraceSlo
+	if err := json.Unmarshal([]byte(openslo.Extension_dynatrace_slos["checkout-availability/0"]), &dynatraceSlo); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(dynatraceSlo.Filter, `type("HTTP_CHECK"),entityName.in("Checkout API")`); diff != nil {
+		t.Error(diff)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+func TestSyntheticsIndicator_shouldbeError_invalidIndicator(t *testing.T) {
+	cases := []struct {
+		replaced string
+		by       string
+		expected string
+	}{
+		{"  syntheticMonitorRef: checkout-api\n---", "  syntheticMonitorRef: unknown\n---", "sli checkout-api-success: bad reference: No object of kind synthetics_http with name unknown"},
+		{"      syntheticMonitorRef: checkout-api\n", "      syntheticMonitorRef: checkout-api\n      thresholdMetric:\n        metricSource:\n          type: prometheus\n", "slo checkout-inline indicator: syntheticMonitorRef cannot be combined with a thresholdMetric or a ratioMetric"},
+	}
+
+	for _, c := range cases {
+		// when
+		err := (&OpenSloDataSource{}).GetOpenSloData(strings.Replace(syntheticsIndicatorYamlSpec, c.replaced, c.by, 1), &diag.Diagnostics{})
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestSyntheticsIndicator_shouldbeWarning_unscrapedMonitor(t *testing.T) {
+	cases := []struct {
+		replaced string
+		by       string
+		summary  string
+		expected string
+	}{
+		{"", "", "Synthetic indicator may have no data", "extension_blackbox_exporter_address is not set"},
+		{"  requests:\n", "  enabled: false\n  requests:\n", "Synthetic indicator has no data", "http monitor checkout-api is disabled, it has no scrape config"},
+		{"  - path: /health\n", "  - path: /login\n    extract:\n    - name: token\n      header: X-Token\n  - path: /health\n    headers:\n    - name: Authorization\n      value: \"{{token}}\"\n", "Synthetic indicator is partial", "http monitor checkout-api requests [1] use variables extracted by previous requests"},
+	}
+
+	for _, c := range cases {
+		// when
+		diagnostics := diag.Diagnostics{}
+		err := (&OpenSloDataSource{}).GetOpenSloData(strings.Replace(syntheticsIndicatorYamlSpec, c.replaced, c.by, 1), &diagnostics)
+
+		// then
+		if err != nil {
+			t.Fatal(err)
+		}
+		warnings := warningsWithSummary(diagnostics, c.summary)
+		if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), c.expected) {
+			t.Errorf("Expected a %q warning containing %q, got %v", c.summary, c.expected, warnings)
+		}
+	}
+
+	// and monitors left out by the selector have no data
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{Selector: &SelectorModel{Kinds: []string{"SLI", "SLO"}}}
+	if err := openslo.GetOpenSloData(syntheticsIndicatorYamlSpec, &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	warnings := warningsWithSummary(diagnostics, "Synthetic indicator has no data")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "http monitor checkout-api is not kept by the selector") {
+		t.Errorf("Expected a selector warning, got %v", warnings)
+	}
+}
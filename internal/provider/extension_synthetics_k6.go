@@ -0,0 +1,330 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// K6Check is a named k6 check() predicate on the response r
+type K6Check struct {
+	Name      string
+	Predicate string
+}
+
+// SyntheticsExtensionRenderK6 renders a k6 script per enabled HTTP monitor, keyed by monitor. Assertions k6 cannot
+// check, auth secrets that are not env: or file: references, and locations produce a warning.
+func (d *OpenSloDataSource) SyntheticsExtensionRenderK6(diagnostics *diag.Diagnostics) {
+	d.Extension_k6_scripts = map[string]string{}
+	for _, monitorName := range sortedKeys(d.Extension_httpmonitor) {
+		monitor := d.Extension_httpmonitor[monitorName]
+		if !monitorEnabled(monitor.Enabled) {
+			continue
+		}
+		if len(monitor.Locations) > 0 {
+			// k6 runs where it is started, cloud load zones are not synthetics locations
+			diagnostics.AddWarning("k6 ignores locations", fmt.Sprintf("http monitor %s locations are not supported by k6", monitorName))
+		}
+		script, unsupported, authErrors := K6Script(monitorName, monitor)
+		for _, field := range unsupported {
+			diagnostics.AddWarning("Cannot render k6 check, skipping", fmt.Sprintf("http monitor %s %s is not supported by k6", monitorName, field))
+		}
+		for _, authError := range authErrors {
+			diagnostics.AddWarning("Cannot render k6 auth, skipping", fmt.Sprintf("http monitor %s %s", monitorName, authError))
+		}
+		d.Extension_k6_scripts[monitorName] = script
+	}
+}
+
+// K6Script returns the k6 script issuing the monitor requests in order with the monitor timeout, each in a group
+// checking its expected response, the fields it cannot check and the auth it cannot render. Failed requests are retried
+// up to the monitor retries, and extracted values are kept in vars for the next requests. The script runs the monitor
+// once, it is scheduled at the monitor frequency by whatever runs k6.
+func K6Script(monitorName string, monitor HTTPMonitorModel) (string, []string, []string) {
+	var script strings.Builder
+	var unsupported []string
+	var authErrors []string
+	var secretFiles []string
+	for _, request := range monitor.Requests {
+		if len(request.Extract) > 0 {
+			script.WriteString("  const vars = {};\n")
+			break
+		}
+	}
+	for i, request := range monitor.Requests {
+		requestName := RequestName(i, request)
+		method := strings.ToUpper(string(request.Method))
+		if method == "" {
+			method = string(GET)
+		}
+		body := "null"
+		if request.Body != "" {
+			body = k6Template(request.Body)
+		}
+		headers := map[string]string{}
+		for _, header := range request.Headers {
+			headers[header.Name] = k6Template(header.Value)
+		}
+		statements, authorization, errs := k6Auth(request.Auth, &secretFiles)
+		for _, err := range errs {
+			authErrors = append(authErrors, fmt.Sprintf("request %s: %s", requestName, err))
+		}
+		if authorization != "" {
+			headers["Authorization"] = authorization
+		}
+		var params []string
+		if len(headers) > 0 {
+			params = append(params, fmt.Sprintf("\"headers\":%s", k6Object(headers)))
+		}
+		if timeout, err := ParseOpenSloDuration(monitor.Timeout); err == nil {
+			// k6 reads string timeouts as Go durations, which have no day or calendar units
+			params = append(params, fmt.Sprintf("\"timeout\":%s", jsLiteral(fmt.Sprintf("%ds", int64(timeout/time.Second)))))
+		}
+
+		checks, requestUnsupported := K6Checks(request.ExpectedResponse)
+		for _, field := range requestUnsupported {
+			unsupported = append(unsupported, fmt.Sprintf("request %s: expectedResponse.%s", requestName, field))
+		}
+
+		script.WriteString(fmt.Sprintf("  group(%s, function () {\n", jsLiteral(requestName)))
+		for _, statement := range statements {
+			script.WriteString(fmt.Sprintf("    %s\n", statement))
+		}
+		httpRequest := fmt.Sprintf("http.request(%s, %s, %s, %s)", jsLiteral(method), k6UrlTemplate(RequestUrl(monitor.Url, request.Path)), body, "{"+strings.Join(params, ",")+"}")
+		var checkObject strings.Builder
+		checkObject.WriteString("{\n")
+		for _, check := range checks {
+			checkObject.WriteString(fmt.Sprintf("      %s: (r) => %s,\n", jsLiteral(check.Name), check.Predicate))
+		}
+		checkObject.WriteString("    }")
+		if monitor.Retries > 0 {
+			script.WriteString(fmt.Sprintf("    const checks = %s;\n", checkObject.String()))
+			script.WriteString("    let res;\n")
+			script.WriteString(fmt.Sprintf("    for (let attempt = 0; attempt <= %d; attempt++) {\n", monitor.Retries))
+			script.WriteString(fmt.Sprintf("      res = %s;\n", httpRequest))
+			script.WriteString("      if (Object.values(checks).every((c) => c(res))) {\n        break;\n      }\n    }\n")
+			script.WriteString("    check(res, checks);\n")
+		} else {
+			script.WriteString(fmt.Sprintf("    const res = %s;\n", httpRequest))
+			script.WriteString(fmt.Sprintf("    check(res, %s);\n", checkObject.String()))
+		}
+		for _, extractor := range request.Extract {
+			script.WriteString(fmt.Sprintf("    vars[%s] = %s;\n", jsLiteral(extractor.Name), k6Extraction(extractor)))
+		}
+		script.WriteString("  });\n")
+	}
+	script.WriteString("}\n")
+
+	var header strings.Builder
+	header.WriteString("import http from \"k6/http\";\n")
+	if strings.Contains(script.String(), "encoding.b64encode(") {
+		header.WriteString("import encoding from \"k6/encoding\";\n")
+	}
+	header.WriteString("import { check, group } from \"k6\";\n\n")
+	header.WriteString(fmt.Sprintf("// Generated from the %s HTTPMonitor, to run every %s\n\n", monitorName, monitor.Frequency))
+	if len(secretFiles) > 0 {
+		// open() is only available in the init context
+		opened := make([]string, len(secretFiles))
+		for i, secretFile := range secretFiles {
+			opened[i] = fmt.Sprintf("open(%s).trim()", jsLiteral(secretFile))
+		}
+		header.WriteString(fmt.Sprintf("const secrets = [%s];\n\n", strings.Join(opened, ", ")))
+	}
+	header.WriteString("function jsonValue(value) {\n  return typeof value === \"string\" ? value : JSON.stringify(value);\n}\n\n")
+	header.WriteString("export default function () {\n")
+	return header.String() + script.String(), unsupported, authErrors
+}
+
+// k6Auth returns the statements run before the request and the expression of its Authorization header. Secrets are
+// read from the environment for env:VAR references, or from the files opened in the init context for file:/path
+// references. Secrets given as plain values are not written in the script, the request is sent without auth.
+func k6Auth(auth AuthModel, secretFiles *[]string) ([]string, string, []string) {
+	var errs []string
+	files := append([]string{}, *secretFiles...)
+	value := func(field string, value string, secret bool) string {
+		prefix, target, ok := secretReference(value)
+		switch {
+		case ok && prefix == SECRET_REF_ENV:
+			return fmt.Sprintf("__ENV[%s]", jsLiteral(target))
+		case ok && prefix == SECRET_REF_FILE:
+			if !containsString(files, target) {
+				files = append(files, target)
+			}
+			for i, file := range files {
+				if file == target {
+					return fmt.Sprintf("secrets[%d]", i)
+				}
+			}
+		case secret:
+			errs = append(errs, fmt.Sprintf("auth.%s must be an env: or file: reference", field))
+		}
+		return jsLiteral(value)
+	}
+
+	var statements []string
+	var authorization string
+	switch auth.Type {
+	case AUTH_BASIC:
+		authorization = fmt.Sprintf("\"Basic \" + encoding.b64encode(%s + \":\" + %s)", value("username", auth.Username, false), value("password", auth.Password, true))
+	case AUTH_BEARER:
+		authorization = fmt.Sprintf("\"Bearer \" + %s", value("token", auth.Token, true))
+	case AUTH_OAUTH2:
+		form := map[string]string{"grant_type": jsLiteral("client_credentials")}
+		if len(auth.Scopes) > 0 {
+			form["scope"] = jsLiteral(strings.Join(auth.Scopes, " "))
+		}
+		clientAuthorization := fmt.Sprintf("\"Basic \" + encoding.b64encode(encodeURIComponent(%s) + \":\" + encodeURIComponent(%s))", value("clientId", auth.ClientId, false), value("clientSecret", auth.ClientSecret, true))
+		statements = append(statements, fmt.Sprintf("const token = http.post(%s, %s, {\"headers\":{\"Authorization\":%s}}).json(\"access_token\");", value("tokenUrl", auth.TokenUrl, false), k6Object(form), clientAuthorization))
+		authorization = "\"Bearer \" + token"
+	}
+	if len(errs) > 0 {
+		return nil, "", errs
+	}
+	*secretFiles = files
+	return statements, authorization, nil
+}
+
+// K6Checks returns the checks of the expected response, and the fields k6 cannot check. Without expected codes, the
+// status must not be an error status. JSON paths are read with the gjson syntax of r.json().
+func K6Checks(expected ResponseModel) ([]K6Check, []string) {
+	var checks []K6Check
+	var unsupported []string
+	if len(expected.Codes) > 0 || len(expected.CodeRanges) > 0 {
+		var conditions []string
+		if len(expected.Codes) > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s.includes(r.status)", jsLiteral(expected.Codes)))
+		}
+		for _, codeRange := range expected.CodeRanges {
+			low, high, err := StatusCodeRangeBounds(codeRange)
+			if err == nil {
+				conditions = append(conditions, fmt.Sprintf("(r.status >= %d && r.status <= %d)", low, high))
+			}
+		}
+		checks = append(checks, K6Check{
+			Name:      fmt.Sprintf("status is one of %s", strings.Join(expectedStatusCodes(expected), ", ")),
+			Predicate: strings.Join(conditions, " || "),
+		})
+	} else {
+		checks = append(checks, K6Check{Name: "status is not an error", Predicate: "r.status < 400"})
+	}
+
+	for _, header := range expected.Headers {
+		value := fmt.Sprintf("r.headers[%s]", jsLiteral(http.CanonicalHeaderKey(header.Name)))
+		switch {
+		case header.Value != "":
+			checks = append(checks, K6Check{Name: fmt.Sprintf("header %s is %s", header.Name, header.Value), Predicate: fmt.Sprintf("%s === %s", value, jsLiteral(header.Value))})
+		case header.Matches != "":
+			checks = append(checks, K6Check{Name: fmt.Sprintf("header %s matches %s", header.Name, header.Matches), Predicate: fmt.Sprintf("new RegExp(%s).test(%s || \"\")", jsLiteral(header.Matches), value)})
+		default:
+			checks = append(checks, K6Check{Name: fmt.Sprintf("header %s is present", header.Name), Predicate: fmt.Sprintf("%s !== undefined", value)})
+		}
+	}
+
+	if expected.PayloadContains != "" {
+		checks = append(checks, K6Check{Name: fmt.Sprintf("body contains %s", expected.PayloadContains), Predicate: fmt.Sprintf("r.body.includes(%s)", jsLiteral(expected.PayloadContains))})
+	}
+	if expected.PayloadNotContains != "" {
+		checks = append(checks, K6Check{Name: fmt.Sprintf("body does not contain %s", expected.PayloadNotContains), Predicate: fmt.Sprintf("!r.body.includes(%s)", jsLiteral(expected.PayloadNotContains))})
+	}
+	if expected.PayloadMatches != "" {
+		checks = append(checks, K6Check{Name: fmt.Sprintf("body matches %s", expected.PayloadMatches), Predicate: fmt.Sprintf("new RegExp(%s).test(r.body)", jsLiteral(expected.PayloadMatches))})
+	}
+
+	for _, assertion := range expected.JsonPath {
+		segments, err := ParseJsonPath(assertion.Path)
+		if err != nil {
+			continue
+		}
+		value := fmt.Sprintf("r.json(%s)", jsLiteral(GjsonPath(segments)))
+		if assertion.Value == "" {
+			checks = append(checks, K6Check{Name: fmt.Sprintf("%s is present", assertion.Path), Predicate: fmt.Sprintf("%s !== undefined", value)})
+		} else {
+			checks = append(checks, K6Check{Name: fmt.Sprintf("%s is %s", assertion.Path, assertion.Value), Predicate: fmt.Sprintf("jsonValue(%s) === %s", value, jsLiteral(assertion.Value))})
+		}
+	}
+
+	if maxLatency, err := time.ParseDuration(expected.MaxLatency); err == nil {
+		checks = append(checks, K6Check{Name: fmt.Sprintf("duration is at most %s", expected.MaxLatency), Predicate: fmt.Sprintf("r.timings.duration <= %d", maxLatency.Milliseconds())})
+	}
+	if expected.MinCertificateValidity != "" {
+		unsupported = append(unsupported, "minCertificateValidity")
+	}
+	return checks, unsupported
+}
+
+// GjsonPath returns the gjson path of JSON path segments, as used by the k6 response json() selector
+func GjsonPath(segments []interface{}) string {
+	escaper := strings.NewReplacer(`\`, `\\`, ".", `\.`, "*", `\*`, "?", `\?`, "|", `\|`, "#", `\#`, "@", `\@`)
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		switch key := segment.(type) {
+		case string:
+			parts[i] = escaper.Replace(key)
+		case int:
+			parts[i] = strconv.Itoa(key)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// k6Extraction returns the expression reading the extractor value from the response res
+func k6Extraction(extractor ExtractorModel) string {
+	switch {
+	case extractor.JsonPath != "":
+		segments, _ := ParseJsonPath(extractor.JsonPath)
+		return fmt.Sprintf("jsonValue(res.json(%s))", jsLiteral(GjsonPath(segments)))
+	case extractor.Header != "":
+		return fmt.Sprintf("res.headers[%s]", jsLiteral(http.CanonicalHeaderKey(extractor.Header)))
+	}
+	return fmt.Sprintf("(res.body.match(new RegExp(%s)) || [])[%d]", jsLiteral(extractor.Regex), extractorRegexGroup(extractor.Regex))
+}
+
+// k6Template returns the JavaScript expression of a value, concatenating the vars it references with {{name}}
+func k6Template(value string) string {
+	return k6TemplateOf(value, "vars[%s]")
+}
+
+// k6UrlTemplate returns the JavaScript expression of a url, with the vars it references URI encoded, as in the check
+func k6UrlTemplate(value string) string {
+	return k6TemplateOf(value, "encodeURIComponent(vars[%s])")
+}
+
+func k6TemplateOf(value string, variable string) string {
+	references := variableReferenceRegex.FindAllStringSubmatchIndex(value, -1)
+	if len(references) == 0 {
+		return jsLiteral(value)
+	}
+	var parts []string
+	start := 0
+	for _, reference := range references {
+		if reference[0] > start {
+			parts = append(parts, jsLiteral(value[start:reference[0]]))
+		}
+		parts = append(parts, fmt.Sprintf(variable, jsLiteral(value[reference[2]:reference[3]])))
+		start = reference[1]
+	}
+	if start < len(value) {
+		parts = append(parts, jsLiteral(value[start:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// k6Object returns the JavaScript object of the keys and their expressions
+func k6Object(values map[string]string) string {
+	entries := []string{}
+	for _, key := range sortedKeys(values) {
+		entries = append(entries, fmt.Sprintf("%s:%s", jsLiteral(key), values[key]))
+	}
+	return "{" + strings.Join(entries, ",") + "}"
+}
+
+// jsLiteral returns the JSON encoding of the value, a valid JavaScript literal
+func jsLiteral(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const k6ExpectedScript = `import http from "k6/http";
+import { check, group } from "k6";
+
+// Generated from the checkout-api HTTPMonitor, to run every 1m
+
+function jsonValue(value) {
+  return typeof value === "string" ? value : JSON.stringify(value);
+}
+
+export default function () {
+  group("login", function () {
+    const res = http.request("POST", "https://checkout.example.com/login", "{\"user\": \"synthetic\"}", {"headers":{"Content-Type":"application/json"},"timeout":"5s"});
+    check(res, {
+      "status is one of 200, 201": (r) => [200,201].includes(r.status),
+      "header Content-Type is application/json": (r) => r.headers["Content-Type"] === "application/json",
+      "header X-Request-Id is present": (r) => r.headers["X-Request-Id"] !== undefined,
+      "body contains token.": (r) => r.body.includes("token."),
+      "body does not contain error": (r) => !r.body.includes("error"),
+      "duration is at most 2s": (r) => r.timings.duration <= 2000,
+    });
+  });
+  group("1", function () {
+    const res = http.request("GET", "https://checkout.example.com/health", null, {"timeout":"5s"});
+    check(res, {
+      "status is not an error": (r) => r.status < 400,
+      "body matches ^ok": (r) => new RegExp("^ok").test(r.body),
+      "$.status is present": (r) => r.json("status") !== undefined,
+    });
+  });
+}
+`
+
+func TestSyntheticsK6_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(blackboxYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(openslo.Extension_k6_scripts, map[string]string{"checkout-api": k6ExpectedScript}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the certificate validity is a warning
+	warnings := warningsWithSummary(diagnostics, "Cannot render k6 check, skipping")
+	if len(warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", warnings)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+const k6RetriesExpectedScript = `import http from "k6/http";
+import encoding from "k6/encoding";
+import { check, group } from "k6";
+
+// Generated from the checkout-api HTTPMonitor, to run every 5m
+
+const secrets = [open("/run/secrets/checkout").trim()];
+
+function jsonValue(value) {
+  return typeof value === "string" ? value : JSON.stringify(value);
+}
+
+export default function () {
+  group("orders", function () {
+    const token = http.post("https://auth.example.com/token", {"grant_type":"client_credentials"}, {"headers":{"Authorization":"Basic " + encoding.b64encode(encodeURIComponent("synthetic") + ":" + encodeURIComponent(secrets[0]))}}).json("access_token");
+    const checks = {
+      "status is one of 200": (r) => [200].includes(r.status),
+    };
+    let res;
+    for (let attempt = 0; attempt <= 2; attempt++) {
+      res = http.request("GET", "https://checkout.example.com/orders", null, {"headers":{"Authorization":"Bearer " + token},"timeout":"30s"});
+      if (Object.values(checks).every((c) => c(res))) {
+        break;
+      }
+    }
+    check(res, checks);
+  });
+  group("health", function () {
+    const checks = {
+      "status is not an error": (r) => r.status < 400,
+    };
+    let res;
+    for (let attempt = 0; attempt <= 2; attempt++) {
+      res = http.request("GET", "https://checkout.example.com/health", null, {"headers":{"Authorization":"Bearer " + __ENV["CHECKOUT_TOKEN"]},"timeout":"30s"});
+      if (Object.values(checks).every((c) => c(res))) {
+        break;
+      }
+    }
+    check(res, checks);
+  });
+}
+`
+
+func TestSyntheticsK6_shouldRetry_authenticatedRequests(t *testing.T) {
+	// when
+	script, unsupported, authErrors := K6Script("checkout-api", HTTPMonitorModel{
+		Url:       "https://checkout.example.com",
+		Frequency: "5m",
+		Timeout:   "30s",
+		Retries:   2,
+		Requests: []RequestModel{
+			{
+				Name:             "orders",
+				Path:             "/orders",
+				Auth:             AuthModel{Type: AUTH_OAUTH2, TokenUrl: "https://auth.example.com/token", ClientId: "synthetic", ClientSecret: "file:/run/secrets/checkout"},
+				ExpectedResponse: ResponseModel{Codes: []int{200}},
+			},
+			{
+				Name: "health",
+				Path: "/health",
+				Auth: AuthModel{Type: AUTH_BEARER, Token: "env:CHECKOUT_TOKEN"},
+			},
+		},
+	})
+
+	// then the token is requested once, and each request is retried until its checks pass
+	if diff := deep.Equal(script, k6RetriesExpectedScript); diff != nil {
+		t.Error(diff)
+	}
+	if len(unsupported) != 0 || len(authErrors) != 0 {
+		t.Errorf("Unexpected unsupported fields %v or auth errors %v", unsupported, authErrors)
+	}
+}
+
+func TestSyntheticsK6_shouldCheck_rangesAndJsonPath(t *testing.T) {
+	// when
+	checks, unsupported := K6Checks(ResponseModel{
+		Codes:      []int{304},
+		CodeRanges: []string{"2xx"},
+		Headers:    []HeaderAssertionModel{{Name: "content-type", Matches: "^application/json"}},
+		JsonPath:   []JsonPathAssertionModel{{Path: "$['orders'][0]['a.b']", Value: "42"}},
+	})
+
+	// then
+	expected := []K6Check{
+		{Name: "status is one of 304, 2xx", Predicate: "[304].includes(r.status) || (r.status >= 200 && r.status <= 299)"},
+		{Name: "header content-type matches ^application/json", Predicate: `new RegExp("^application/json").test(r.headers["Content-Type"] || "")`},
+		{Name: "$['orders'][0]['a.b'] is 42", Predicate: `jsonValue(r.json("orders.0.a\\.b")) === "42"`},
+	}
+	if diff := deep.Equal(checks, expected); diff != nil {
+		t.Error(diff)
+	}
+	if len(unsupported) != 0 {
+		t.Errorf("Unexpected unsupported fields %v", unsupported)
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	DNS_DEFAULT_RECORD_TYPE = "A"
+	TLS_DEFAULT_PORT        = 443
+)
+
+var dnsRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "PTR", "SRV", "TXT"}
+
+// normalizeTCPMonitor checks the host and port of the monitor
+func normalizeTCPMonitor(monitor TCPMonitorModel) (TCPMonitorModel, error) {
+	if monitor.Host == "" {
+		return monitor, fmt.Errorf("host is required")
+	}
+	if err := validatePort(monitor.Port); err != nil {
+		return monitor, err
+	}
+	return monitor, nil
+}
+
+// normalizeDNSMonitor checks the monitor, the record type defaults to A
+func normalizeDNSMonitor(monitor DNSMonitorModel) (DNSMonitorModel, error) {
+	if monitor.Hostname == "" {
+		return monitor, fmt.Errorf("hostname is required")
+	}
+	monitor.RecordType = strings.ToUpper(monitor.RecordType)
+	if monitor.RecordType == "" {
+		monitor.RecordType = DNS_DEFAULT_RECORD_TYPE
+	}
+	if !containsString(dnsRecordTypes, monitor.RecordType) {
+		return monitor, fmt.Errorf("recordType %s is not supported, expected one of %v", monitor.RecordType, dnsRecordTypes)
+	}
+	if monitor.Resolver != "" {
+		if _, _, err := net.SplitHostPort(monitor.Resolver); err != nil {
+			return monitor, fmt.Errorf("resolver: expected host:port, got %q", monitor.Resolver)
+		}
+	}
+	return monitor, nil
+}
+
+// normalizeGRPCMonitor checks the target of the monitor. An empty health service checks the whole server.
+func normalizeGRPCMonitor(monitor GRPCMonitorModel) (GRPCMonitorModel, error) {
+	if _, _, err := net.SplitHostPort(monitor.Target); err != nil {
+		return monitor, fmt.Errorf("target: expected host:port, got %q", monitor.Target)
+	}
+	return monitor, nil
+}
+
+// normalizeTLSMonitor checks the monitor, the port defaults to 443 and the server name to the host
+func normalizeTLSMonitor(monitor TLSMonitorModel) (TLSMonitorModel, error) {
+	if monitor.Host == "" {
+		return monitor, fmt.Errorf("host is required")
+	}
+	if monitor.Port == 0 {
+		monitor.Port = TLS_DEFAULT_PORT
+	}
+	if err := validatePort(monitor.Port); err != nil {
+		return monitor, err
+	}
+	if monitor.ServerName == "" {
+		monitor.ServerName = monitor.Host
+	}
+	if monitor.MinCertificateValidity != "" {
+		if _, err := ParseOpenSloDuration(monitor.MinCertificateValidity); err != nil {
+			return monitor, fmt.Errorf("minCertificateValidity: %w", err)
+		}
+	}
+	return monitor, nil
+}
+
+func validatePort(port int64) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", port)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MONITOR_DEFAULT_FREQUENCY = "15m"
+	MONITOR_DEFAULT_TIMEOUT   = "10s"
+	MONITOR_MIN_FREQUENCY     = time.Minute
+)
+
+// normalizeSchedule checks the schedule fields of a monitor and sets their defaults: every 15 minutes, a 10 seconds
+// timeout, no retries and enabled. Durations are OpenSLO durations, the frequency is in whole minutes, as most
+// synthetics backends schedule by minute.
+func normalizeSchedule(frequency *string, timeout *string, retries int64, enabled **bool) error {
+	if *frequency == "" {
+		*frequency = MONITOR_DEFAULT_FREQUENCY
+	}
+	parsedFrequency, err := ParseOpenSloDuration(*frequency)
+	if err != nil {
+		return fmt.Errorf("frequency: %w", err)
+	}
+	if parsedFrequency < MONITOR_MIN_FREQUENCY || parsedFrequency%time.Minute != 0 {
+		return fmt.Errorf("frequency: expected whole minutes, got %s", *frequency)
+	}
+
+	if *timeout == "" {
+		*timeout = MONITOR_DEFAULT_TIMEOUT
+	}
+	parsedTimeout, err := ParseOpenSloDuration(*timeout)
+	if err != nil {
+		return fmt.Errorf("timeout: %w", err)
+	}
+	if parsedTimeout <= 0 {
+		return fmt.Errorf("timeout: expected a positive duration, got %s", *timeout)
+	}
+	if parsedTimeout > parsedFrequency {
+		return fmt.Errorf("timeout: %s is longer than the frequency %s", *timeout, *frequency)
+	}
+
+	if retries < 0 {
+		return fmt.Errorf("retries: expected a non-negative number, got %d", retries)
+	}
+	if *enabled == nil {
+		enabledDefault := true
+		*enabled = &enabledDefault
+	}
+	return nil
+}
+
+// monitorDuration parses a schedule duration of a monitor, or its default when not set
+func monitorDuration(duration string, defaultDuration string) time.Duration {
+	parsed, err := ParseOpenSloDuration(duration)
+	if err != nil {
+		parsed, _ = ParseOpenSloDuration(defaultDuration)
+	}
+	return parsed
+}
+
+// monitorFrequencyMinutes returns the frequency of a monitor in minutes, or the default frequency when not set
+func monitorFrequencyMinutes(frequency string) int {
+	return int(monitorDuration(frequency, MONITOR_DEFAULT_FREQUENCY) / time.Minute)
+}
+
+// monitorEnabled returns whether a monitor runs, monitors are enabled by default
+func monitorEnabled(enabled *bool) bool {
+	return enabled == nil || *enabled
+}
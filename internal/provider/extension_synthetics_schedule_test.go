@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const syntheticsScheduleYamlSpec = `
+apiVersion: openslo_synthetics/v1
+kind: HTTPMonitor
+metadata:
+  name: checkout-api
+spec:
+  url: https://checkout.example.com
+  frequency: 5m
+  locations:
+  - GEOLOCATION-9999453BE4BDB3CD
+  - GEOLOCATION-B4B9167CAAA88F6A
+  timeout: 30s
+  retries: 2
+  enabled: false
+  requests:
+  - path: /health
+`
+
+func TestSyntheticsSchedule_shouldbeValid_yamlSpec(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(syntheticsScheduleYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	monitor := openslo.Extension_httpmonitor["checkout-api"]
+	if diff := deep.Equal([]interface{}{monitor.Frequency, monitor.Timeout, monitor.Retries, *monitor.Enabled}, []interface{}{"5m", "30s", int64(2), false}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the dynatrace monitor runs on the schedule
+	var dynatraceMonitor DynatraceHttpMonitor
+	if err := json.Unmarshal([]byte(openslo.Extension_dynatrace_http_monitors["checkout-api"]), &dynatraceMonitor); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal([]interface{}{dynatraceMonitor.FrequencyMin, dynatraceMonitor.Enabled, dynatraceMonitor.Locations}, []interface{}{5, false, []string{"GEOLOCATION-9999453BE4BDB3CD", "GEOLOCATION-B4B9167CAAA88F6A"}}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+func TestSyntheticsSchedule_shouldbeError_invalidSchedule(t *testing.T) {
+	cases := []struct {
+		replaced string
+		by       string
+		expected string
+	}{
+		{"frequency: 5m", "frequency: 5", "http monitor checkout-api: frequency"},
+		{"frequency: 5m", "frequency: 1m30s", "http monitor checkout-api: frequency"},
+		{"frequency: 5m", "frequency: 90s", "frequency: expected whole minutes, got 90s"},
+		{"timeout: 30s", "timeout: 500ms", "http monitor checkout-api: timeout"},
+		{"timeout: 30s", "timeout: 10m", "timeout: 10m is longer than the frequency 5m"},
+		{"retries: 2", "retries: -1", "retries: expected a non-negative number, got -1"},
+	}
+
+	for _, c := range cases {
+		// when
+		err := (&OpenSloDataSource{}).GetOpenSloData(strings.Replace(syntheticsScheduleYamlSpec, c.replaced, c.by, 1), &diag.Diagnostics{})
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestSyntheticsSchedule_shouldbeValid_openSloDurations(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(strings.Replace(syntheticsScheduleYamlSpec, "frequency: 5m", "frequency: 1d", 1), "timeout: 30s", "timeout: 2m", 1), &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dynatraceMonitor DynatraceHttpMonitor
+	if err := json.Unmarshal([]byte(openslo.Extension_dynatrace_http_monitors["checkout-api"]), &dynatraceMonitor); err != nil {
+		t.Fatal(err)
+	}
+	if dynatraceMonitor.FrequencyMin != 1440 {
+		t.Errorf("Expected a daily dynatrace monitor, got every %d minutes", dynatraceMonitor.FrequencyMin)
+	}
+
+	// and the blackbox scrape config uses prometheus durations
+	scrapeConfig := BlackboxScrapeConfig("checkout-api", openslo.Extension_httpmonitor["checkout-api"], BLACKBOX_DEFAULT_EXPORTER_ADDRESS)
+	if diff := deep.Equal([]string{scrapeConfig.ScrapeInterval, scrapeConfig.ScrapeTimeout}, []string{"1d", "2m"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSyntheticsSchedule_shouldRetry_failedRequests(t *testing.T) {
+	// given
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	enabled := true
+	disabled := false
+	monitors := map[string]HTTPMonitorModel{
+		"checkout-api": {Url: server.URL, Timeout: "5s", Retries: 2, Enabled: &enabled, Requests: []RequestModel{{Path: "/health"}}},
+		"disabled":     {Url: server.URL, Enabled: &disabled, Requests: []RequestModel{{Path: "/health"}}},
+	}
+
+	// when
+	checker := SyntheticsChecker{Client: server.Client()}
+	results := checker.CheckHttpMonitors(context.Background(), monitors)
+
+	// then
+	if !results["checkout-api/0"].Passed || calls != 3 {
+		t.Errorf("Expected the third attempt to pass, got %v after %d calls", results["checkout-api/0"].Failures, calls)
+	}
+	if _, ok := results["disabled/0"]; ok {
+		t.Error("Expected disabled monitors not to be checked")
+	}
+}
+
+func TestSyntheticsSchedule_shouldbeValid_k6(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsScheduleYamlSpec, "enabled: false", "enabled: true", 1), &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and failed requests are retried
+	script := openslo.Extension_k6_scripts["checkout-api"]
+	for _, expected := range []string{
+		"// Generated from the checkout-api HTTPMonitor, to run every 5m",
+		"    for (let attempt = 0; attempt <= 2; attempt++) {\n      res = http.request(\"GET\", \"https://checkout.example.com/health\", null, {\"timeout\":\"30s\"});\n",
+		"    check(res, checks);\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected the k6 script to contain %q, but got %s", expected, script)
+		}
+	}
+
+	// and locations are a warning
+	warnings := warningsWithSummary(diagnostics, "k6 ignores locations")
+	if len(warnings) != 1 || warnings[0].Detail() != "http monitor checkout-api locations are not supported by k6" {
+		t.Errorf("Expected a locations warning, got %v", warnings)
+	}
+}
+
+func TestSyntheticsSchedule_shouldSkip_disabledK6Monitors(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(syntheticsScheduleYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(openslo.Extension_k6_scripts) != 0 {
+		t.Errorf("Expected no k6 script for disabled monitors, got %v", openslo.Extension_k6_scripts)
+	}
+}
+
+func TestSyntheticsSchedule_shouldSkip_disabledPlaywrightMonitors(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(strings.Replace(syntheticsBrowserYamlSpec, "retries: 1", "enabled: false", 1), &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(openslo.Extension_playwright_scripts) != 0 {
+		t.Errorf("Expected no playwright script for disabled monitors, got %v", openslo.Extension_playwright_scripts)
+	}
+
+	// and the dynatrace monitor is rendered disabled
+	var dynatraceMonitor DynatraceBrowserMonitor
+	if err := json.Unmarshal([]byte(openslo.Extension_dynatrace_browser_monitors["checkout-flow"]), &dynatraceMonitor); err != nil {
+		t.Fatal(err)
+	}
+	if dynatraceMonitor.Enabled {
+		t.Error("Expected the dynatrace monitor to be disabled")
+	}
+}
@@ -12,6 +12,45 @@ var HeaderSchema = types.ObjectType{
 	},
 }
 
+var HeaderAssertionSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":    types.StringType,
+		"value":   types.StringType,
+		"matches": types.StringType,
+	},
+}
+
+var JsonPathAssertionSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"path":  types.StringType,
+		"value": types.StringType,
+	},
+}
+
+var AuthSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"type":          types.StringType,
+		"username":      types.StringType,
+		"password":      types.StringType,
+		"token":         types.StringType,
+		"token_url":     types.StringType,
+		"client_id":     types.StringType,
+		"client_secret": types.StringType,
+		"scopes": types.ListType{
+			ElemType: types.StringType,
+		},
+	},
+}
+
+var ExtractorSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":      types.StringType,
+		"json_path": types.StringType,
+		"header":    types.StringType,
+		"regex":     types.StringType,
+	},
+}
+
 var RequestSchema = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
 		"name":        types.StringType,
@@ -24,12 +63,30 @@ var RequestSchema = types.ObjectType{
 		"path":   types.StringType,
 		"expected_response": types.ObjectType{
 			AttrTypes: map[string]attr.Type{
-				"codes":                types.NumberType,
+				"codes": types.ListType{
+					ElemType: types.NumberType,
+				},
+				"code_ranges": types.ListType{
+					ElemType: types.StringType,
+				},
+				"headers": types.ListType{
+					ElemType: HeaderAssertionSchema,
+				},
 				"payload_contains":     types.StringType,
 				"payload_not_contains": types.StringType,
-				"dt_postprocessing":    types.StringType,
+				"payload_matches":      types.StringType,
+				"json_path": types.ListType{
+					ElemType: JsonPathAssertionSchema,
+				},
+				"max_latency":              types.StringType,
+				"min_certificate_validity": types.StringType,
+				"dt_postprocessing":        types.StringType,
 			},
 		},
+		"auth": AuthSchema,
+		"extract": types.ListType{
+			ElemType: ExtractorSchema,
+		},
 	},
 }
 
@@ -40,17 +97,109 @@ var HTTPMonitorSchema = types.ObjectType{
 		"requests": types.ListType{
 			ElemType: RequestSchema,
 		},
+		"frequency": types.StringType,
+		"locations": types.ListType{
+			ElemType: types.StringType,
+		},
+		"timeout":     types.StringType,
+		"retries":     types.NumberType,
+		"enabled":     types.BoolType,
 		"service":     ServiceSchema,
 		"service_ref": types.StringType,
 	},
 }
 
+var BrowserStepSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"action":   types.StringType,
+		"url":      types.StringType,
+		"selector": types.StringType,
+		"value":    types.StringType,
+		"text":     types.StringType,
+		"name":     types.StringType,
+	},
+}
+
 var BrowserMonitorSchema = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
-		"metadata":    MetadataSchema,
+		"metadata": MetadataSchema,
+		"url":      types.StringType,
+		"script":   types.StringType,
+		"steps": types.ListType{
+			ElemType: BrowserStepSchema,
+		},
+		"frequency": types.StringType,
+		"locations": types.ListType{
+			ElemType: types.StringType,
+		},
+		"timeout":     types.StringType,
+		"retries":     types.NumberType,
+		"enabled":     types.BoolType,
+		"service":     ServiceSchema,
+		"service_ref": types.StringType,
+	},
+}
+
+var SyntheticsCheckResultSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"monitor":     types.StringType,
+		"request":     types.StringType,
+		"method":      types.StringType,
 		"url":         types.StringType,
-		"script":      types.StringType,
+		"status_code": types.NumberType,
+		"duration_ms": types.NumberType,
+		"passed":      types.BoolType,
+		"failures": types.ListType{
+			ElemType: types.StringType,
+		},
+	},
+}
+
+var TCPMonitorSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"metadata":        MetadataSchema,
+		"host":            types.StringType,
+		"port":            types.NumberType,
+		"send":            types.StringType,
+		"expect_contains": types.StringType,
+		"service":         ServiceSchema,
+		"service_ref":     types.StringType,
+	},
+}
+
+var DNSMonitorSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"metadata":    MetadataSchema,
+		"hostname":    types.StringType,
+		"record_type": types.StringType,
+		"resolver":    types.StringType,
+		"expected_answers": types.ListType{
+			ElemType: types.StringType,
+		},
 		"service":     ServiceSchema,
 		"service_ref": types.StringType,
 	},
 }
+
+var GRPCMonitorSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"metadata":       MetadataSchema,
+		"target":         types.StringType,
+		"health_service": types.StringType,
+		"tls":            types.BoolType,
+		"service":        ServiceSchema,
+		"service_ref":    types.StringType,
+	},
+}
+
+var TLSMonitorSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"metadata":                 MetadataSchema,
+		"host":                     types.StringType,
+		"port":                     types.NumberType,
+		"server_name":              types.StringType,
+		"min_certificate_validity": types.StringType,
+		"service":                  ServiceSchema,
+		"service_ref":              types.StringType,
+	},
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -35,6 +36,7 @@ func TestSynthetics_shouldbeValid_httpMonitorYamlSpec(t *testing.T) {
           path: /my-other-path
 `
 
+	enabled := true
 	expected := HTTPMonitorModel{
 		Metadata: MetadataModel{
 			DisplayName: "My Monitor",
@@ -68,6 +70,9 @@ func TestSynthetics_shouldbeValid_httpMonitorYamlSpec(t *testing.T) {
 				Path:   "/my-other-path",
 			},
 		},
+		Frequency: "15m",
+		Timeout:   "10s",
+		Enabled:   &enabled,
 	}
 
 	// when
@@ -101,13 +106,17 @@ func TestSynthetics_shouldbeValid_browserMonitorYamlSpec(t *testing.T) {
             abc
 `
 
+	enabled := true
 	expected := BrowserMonitorModel{
 		Metadata: MetadataModel{
 			DisplayName: "My Monitor",
 			Name:        "my-monitor",
 		},
-		Url:    "https://my-host.com",
-		Script: "console.log(\"hello\")\nabc\n",
+		Url:       "https://my-host.com",
+		Script:    "console.log(\"hello\")\nabc\n",
+		Frequency: "15m",
+		Timeout:   "10s",
+		Enabled:   &enabled,
 	}
 
 	// when
@@ -182,6 +191,7 @@ func TestSynthetics_shouldbeValid_multiYamlSpecWithRefs(t *testing.T) {
 		Description: "This service does blablabla",
 	}
 
+	enabled := true
 	expectedBrowser := BrowserMonitorModel{
 		Metadata: MetadataModel{
 			DisplayName: "My Monitor",
@@ -189,6 +199,9 @@ func TestSynthetics_shouldbeValid_multiYamlSpecWithRefs(t *testing.T) {
 		},
 		Url:        "https://my-host.com",
 		Script:     "console.log(\"hello\")\nabc\n",
+		Frequency:  "15m",
+		Timeout:    "10s",
+		Enabled:    &enabled,
 		ServiceRef: "my-service",
 		Service:    expectedService,
 	}
@@ -226,6 +239,9 @@ func TestSynthetics_shouldbeValid_multiYamlSpecWithRefs(t *testing.T) {
 				Path:   "/my-other-path",
 			},
 		},
+		Frequency:  "15m",
+		Timeout:    "10s",
+		Enabled:    &enabled,
 		ServiceRef: "my-service",
 		Service:    expectedService,
 	}
@@ -251,3 +267,139 @@ func TestSynthetics_shouldbeValid_multiYamlSpecWithRefs(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestSynthetics_shouldbeValid_networkMonitorYamlSpecs(t *testing.T) {
+	// given
+	yamlSpec := `
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: payments
+spec:
+  description: Payments
+---
+apiVersion: openslo_synthetics/v1
+kind: TCPMonitor
+metadata:
+  name: postgres
+spec:
+  host: db.example.com
+  port: 5432
+  serviceRef: payments
+---
+apiVersion: openslo_synthetics/v1
+kind: DNSMonitor
+metadata:
+  name: api-dns
+spec:
+  hostname: api.example.com
+  recordType: cname
+  resolver: 1.1.1.1:53
+  expectedAnswers:
+  - lb.example.com.
+---
+apiVersion: openslo_synthetics/v1
+kind: DNSMonitor
+metadata:
+  name: www-dns
+spec:
+  hostname: www.example.com
+---
+apiVersion: openslo_synthetics/v1
+kind: GRPCMonitor
+metadata:
+  name: ledger
+spec:
+  target: ledger.example.com:443
+  healthService: ledger.v1.Ledger
+  tls: true
+  serviceRef: payments
+---
+apiVersion: openslo_synthetics/v1
+kind: TLSMonitor
+metadata:
+  name: api-certificate
+spec:
+  host: api.example.com
+  minCertificateValidity: 14d
+`
+
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(yamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	tcp := openslo.Extension_tcpmonitor["postgres"]
+	if tcp.Host != "db.example.com" || tcp.Port != 5432 || tcp.Service.Metadata.Name != "payments" {
+		t.Errorf("Unexpected tcp monitor %+v", tcp)
+	}
+	if diff := deep.Equal(openslo.Extension_dnsmonitor["api-dns"], DNSMonitorModel{
+		Metadata:        MetadataModel{Name: "api-dns"},
+		Hostname:        "api.example.com",
+		RecordType:      "CNAME",
+		Resolver:        "1.1.1.1:53",
+		ExpectedAnswers: []string{"lb.example.com."},
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if openslo.Extension_dnsmonitor["www-dns"].RecordType != "A" {
+		t.Errorf("Expected the A record type by default, got %s", openslo.Extension_dnsmonitor["www-dns"].RecordType)
+	}
+	grpc := openslo.Extension_grpcmonitor["ledger"]
+	if grpc.Target != "ledger.example.com:443" || grpc.HealthService != "ledger.v1.Ledger" || !grpc.Tls || grpc.Service.Metadata.Name != "payments" {
+		t.Errorf("Unexpected grpc monitor %+v", grpc)
+	}
+	if diff := deep.Equal(openslo.Extension_tlsmonitor["api-certificate"], TLSMonitorModel{
+		Metadata:               MetadataModel{Name: "api-certificate"},
+		Host:                   "api.example.com",
+		Port:                   443,
+		ServerName:             "api.example.com",
+		MinCertificateValidity: "14d",
+	}); diff != nil {
+		t.Error(diff)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+
+	// and monitors can be selected by kind
+	openslo = OpenSloDataSource{Selector: &SelectorModel{Kinds: []string{"DNSMonitor"}}}
+	err = openslo.GetOpenSloData(yamlSpec, &diag.Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(openslo.Extension_dnsmonitor) != 2 || len(openslo.Extension_tcpmonitor) != 0 || len(openslo.Extension_grpcmonitor) != 0 || len(openslo.Extension_tlsmonitor) != 0 {
+		t.Errorf("Expected only the DNS monitors, got %v", openslo.Extension_dnsmonitor)
+	}
+}
+
+func TestSynthetics_shouldbeError_invalidNetworkMonitors(t *testing.T) {
+	cases := []struct {
+		spec     string
+		expected string
+	}{
+		{"kind: TCPMonitor\nmetadata:\n  name: m\nspec:\n  host: db\n  port: 70000", "tcp monitor m: port must be between 1 and 65535"},
+		{"kind: TCPMonitor\nmetadata:\n  name: m\nspec:\n  host: db\n  port: 5432\n  serviceRef: missing", "No object of kind synthetics_tcp with name missing"},
+		{"kind: DNSMonitor\nmetadata:\n  name: m\nspec:\n  hostname: example.com\n  recordType: SOA", "dns monitor m: recordType SOA is not supported"},
+		{"kind: DNSMonitor\nmetadata:\n  name: m\nspec:\n  hostname: example.com\n  resolver: 1.1.1.1", "dns monitor m: resolver: expected host:port"},
+		{"kind: GRPCMonitor\nmetadata:\n  name: m\nspec:\n  target: ledger", "grpc monitor m: target: expected host:port"},
+		{"kind: TLSMonitor\nmetadata:\n  name: m\nspec:\n  minCertificateValidity: 14d", "tls monitor m: host is required"},
+		{"kind: TLSMonitor\nmetadata:\n  name: m\nspec:\n  host: example.com\n  minCertificateValidity: 2 weeks", "tls monitor m: minCertificateValidity"},
+	}
+
+	for _, c := range cases {
+		// when
+		openslo := OpenSloDataSource{}
+		err := openslo.GetOpenSloData("apiVersion: openslo_synthetics/v1\n"+c.spec, &diag.Diagnostics{})
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("Expected an error containing %q, got %v", c.expected, err)
+		}
+	}
+}
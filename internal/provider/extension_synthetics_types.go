@@ -16,25 +16,79 @@ type HTTPMonitorModel struct {
 	Metadata   MetadataModel  `tfsdk:"metadata" yaml:"metadata"`
 	Url        string         `tfsdk:"url" yaml:"url"`
 	Requests   []RequestModel `tfsdk:"requests" yaml:"requests"`
+	Frequency  string         `tfsdk:"frequency" yaml:"frequency"`
+	Locations  []string       `tfsdk:"locations" yaml:"locations"`
+	Timeout    string         `tfsdk:"timeout" yaml:"timeout"`
+	Retries    int64          `tfsdk:"retries" yaml:"retries"`
+	Enabled    *bool          `tfsdk:"enabled" yaml:"enabled"`
 	Service    ServiceModel   `tfsdk:"service" yaml:"-"`
 	ServiceRef string         `tfsdk:"service_ref" yaml:"serviceRef"`
 }
 
 type RequestModel struct {
-	Name             string        `tfsdk:"name" yaml:"name"`
-	Description      string        `tfsdk:"description" yaml:"description"`
-	Headers          []HeaderModel `tfsdk:"headers" yaml:"headers"`
-	Body             string        `tfsdk:"body" yaml:"body"`
-	Method           HTTPMethod    `tfsdk:"method" yaml:"method"`
-	Path             string        `tfsdk:"path" yaml:"path"`
-	ExpectedResponse ResponseModel `tfsdk:"expected_response" yaml:"expectedResponse"`
+	Name             string           `tfsdk:"name" yaml:"name"`
+	Description      string           `tfsdk:"description" yaml:"description"`
+	Headers          []HeaderModel    `tfsdk:"headers" yaml:"headers"`
+	Body             string           `tfsdk:"body" yaml:"body"`
+	Method           HTTPMethod       `tfsdk:"method" yaml:"method"`
+	Path             string           `tfsdk:"path" yaml:"path"`
+	ExpectedResponse ResponseModel    `tfsdk:"expected_response" yaml:"expectedResponse"`
+	Auth             AuthModel        `tfsdk:"auth" yaml:"auth"`
+	Extract          []ExtractorModel `tfsdk:"extract" yaml:"extract"`
+}
+
+// ExtractorModel stores a value of the response as a variable, read from a JSON path, a header or a regex
+type ExtractorModel struct {
+	Name     string `tfsdk:"name" yaml:"name"`
+	JsonPath string `tfsdk:"json_path" yaml:"jsonPath"`
+	Header   string `tfsdk:"header" yaml:"header"`
+	Regex    string `tfsdk:"regex" yaml:"regex"`
+}
+
+type AuthType string
+
+const (
+	AUTH_BASIC  AuthType = "basic"
+	AUTH_BEARER AuthType = "bearer"
+	AUTH_OAUTH2 AuthType = "oauth2"
+)
+
+// AuthModel holds the credentials of a request, an empty type sends the request without authentication
+type AuthModel struct {
+	Type         AuthType `tfsdk:"type" yaml:"type"`
+	Username     string   `tfsdk:"username" yaml:"username"`
+	Password     string   `tfsdk:"password" yaml:"password"`
+	Token        string   `tfsdk:"token" yaml:"token"`
+	TokenUrl     string   `tfsdk:"token_url" yaml:"tokenUrl"`
+	ClientId     string   `tfsdk:"client_id" yaml:"clientId"`
+	ClientSecret string   `tfsdk:"client_secret" yaml:"clientSecret"`
+	Scopes       []string `tfsdk:"scopes" yaml:"scopes"`
 }
 
 type ResponseModel struct {
-	Codes                   []int  `tfsdk:"codes" yaml:"code"`
-	PayloadContains         string `tfsdk:"payload_contains" yaml:"payloadContains"`
-	PayloadNotContains      string `tfsdk:"payload_not_contains" yaml:"payloadNotContains"`
-	DynatracePostProcessing string `tfsdk:"dt_postprocessing" yaml:"dynatrace_postprocessing"`
+	Codes                   []int                    `tfsdk:"codes" yaml:"code"`
+	CodeRanges              []string                 `tfsdk:"code_ranges" yaml:"codeRanges"`
+	Headers                 []HeaderAssertionModel   `tfsdk:"headers" yaml:"headers"`
+	PayloadContains         string                   `tfsdk:"payload_contains" yaml:"payloadContains"`
+	PayloadNotContains      string                   `tfsdk:"payload_not_contains" yaml:"payloadNotContains"`
+	PayloadMatches          string                   `tfsdk:"payload_matches" yaml:"payloadMatches"`
+	JsonPath                []JsonPathAssertionModel `tfsdk:"json_path" yaml:"jsonPath"`
+	MaxLatency              string                   `tfsdk:"max_latency" yaml:"maxLatency"`
+	MinCertificateValidity  string                   `tfsdk:"min_certificate_validity" yaml:"minCertificateValidity"`
+	DynatracePostProcessing string                   `tfsdk:"dt_postprocessing" yaml:"dynatrace_postprocessing"`
+}
+
+// HeaderAssertionModel checks a response header: equal to value, matching the regex, or present when both are empty
+type HeaderAssertionModel struct {
+	Name    string `tfsdk:"name" yaml:"name"`
+	Value   string `tfsdk:"value" yaml:"value"`
+	Matches string `tfsdk:"matches" yaml:"matches"`
+}
+
+// JsonPathAssertionModel checks a value of the JSON response body: equal to value, or present when value is empty
+type JsonPathAssertionModel struct {
+	Path  string `tfsdk:"path" yaml:"path"`
+	Value string `tfsdk:"value" yaml:"value"`
 }
 
 type HeaderModel struct {
@@ -43,9 +97,76 @@ type HeaderModel struct {
 }
 
 type BrowserMonitorModel struct {
-	Metadata   MetadataModel `tfsdk:"metadata" yaml:"metadata"`
-	Url        string        `tfsdk:"url" yaml:"url"`
-	Script     string        `tfsdk:"script" yaml:"script"`
-	Service    ServiceModel  `tfsdk:"service" yaml:"-"`
-	ServiceRef string        `tfsdk:"service_ref" yaml:"serviceRef"`
+	Metadata   MetadataModel      `tfsdk:"metadata" yaml:"metadata"`
+	Url        string             `tfsdk:"url" yaml:"url"`
+	Script     string             `tfsdk:"script" yaml:"script"`
+	Steps      []BrowserStepModel `tfsdk:"steps" yaml:"steps"`
+	Frequency  string             `tfsdk:"frequency" yaml:"frequency"`
+	Locations  []string           `tfsdk:"locations" yaml:"locations"`
+	Timeout    string             `tfsdk:"timeout" yaml:"timeout"`
+	Retries    int64              `tfsdk:"retries" yaml:"retries"`
+	Enabled    *bool              `tfsdk:"enabled" yaml:"enabled"`
+	Service    ServiceModel       `tfsdk:"service" yaml:"-"`
+	ServiceRef string             `tfsdk:"service_ref" yaml:"serviceRef"`
+}
+
+type BrowserAction string
+
+const (
+	BROWSER_NAVIGATE     BrowserAction = "navigate"
+	BROWSER_CLICK        BrowserAction = "click"
+	BROWSER_FILL         BrowserAction = "fill"
+	BROWSER_WAIT_FOR     BrowserAction = "waitFor"
+	BROWSER_ASSERT_TEXT  BrowserAction = "assertText"
+	BROWSER_ASSERT_TITLE BrowserAction = "assertTitle"
+	BROWSER_SCREENSHOT   BrowserAction = "screenshot"
+)
+
+// BrowserStepModel is a step of a browser monitor. Selectors are CSS selectors.
+type BrowserStepModel struct {
+	Action   BrowserAction `tfsdk:"action" yaml:"action"`
+	Url      string        `tfsdk:"url" yaml:"url"`
+	Selector string        `tfsdk:"selector" yaml:"selector"`
+	Value    string        `tfsdk:"value" yaml:"value"`
+	Text     string        `tfsdk:"text" yaml:"text"`
+	Name     string        `tfsdk:"name" yaml:"name"`
+}
+
+type TCPMonitorModel struct {
+	Metadata       MetadataModel `tfsdk:"metadata" yaml:"metadata"`
+	Host           string        `tfsdk:"host" yaml:"host"`
+	Port           int64         `tfsdk:"port" yaml:"port"`
+	Send           string        `tfsdk:"send" yaml:"send"`
+	ExpectContains string        `tfsdk:"expect_contains" yaml:"expectContains"`
+	Service        ServiceModel  `tfsdk:"service" yaml:"-"`
+	ServiceRef     string        `tfsdk:"service_ref" yaml:"serviceRef"`
+}
+
+type DNSMonitorModel struct {
+	Metadata        MetadataModel `tfsdk:"metadata" yaml:"metadata"`
+	Hostname        string        `tfsdk:"hostname" yaml:"hostname"`
+	RecordType      string        `tfsdk:"record_type" yaml:"recordType"`
+	Resolver        string        `tfsdk:"resolver" yaml:"resolver"`
+	ExpectedAnswers []string      `tfsdk:"expected_answers" yaml:"expectedAnswers"`
+	Service         ServiceModel  `tfsdk:"service" yaml:"-"`
+	ServiceRef      string        `tfsdk:"service_ref" yaml:"serviceRef"`
+}
+
+type GRPCMonitorModel struct {
+	Metadata      MetadataModel `tfsdk:"metadata" yaml:"metadata"`
+	Target        string        `tfsdk:"target" yaml:"target"`
+	HealthService string        `tfsdk:"health_service" yaml:"healthService"`
+	Tls           bool          `tfsdk:"tls" yaml:"tls"`
+	Service       ServiceModel  `tfsdk:"service" yaml:"-"`
+	ServiceRef    string        `tfsdk:"service_ref" yaml:"serviceRef"`
+}
+
+type TLSMonitorModel struct {
+	Metadata               MetadataModel `tfsdk:"metadata" yaml:"metadata"`
+	Host                   string        `tfsdk:"host" yaml:"host"`
+	Port                   int64         `tfsdk:"port" yaml:"port"`
+	ServerName             string        `tfsdk:"server_name" yaml:"serverName"`
+	MinCertificateValidity string        `tfsdk:"min_certificate_validity" yaml:"minCertificateValidity"`
+	Service                ServiceModel  `tfsdk:"service" yaml:"-"`
+	ServiceRef             string        `tfsdk:"service_ref" yaml:"serviceRef"`
 }
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const ALERTMANAGER_BLACKHOLE_RECEIVER = "openslo-blackhole"
+
+var alertmanagerGroupBy = []string{"alertname", PROMETHEUS_LABEL_SLO, PROMETHEUS_LABEL_OBJECTIVE}
+
+type AlertmanagerConfig struct {
+	Global       *AlertmanagerGlobal       `yaml:"global,omitempty"`
+	Route        AlertmanagerRoute         `yaml:"route"`
+	Receivers    []AlertmanagerReceiver    `yaml:"receivers"`
+	InhibitRules []AlertmanagerInhibitRule `yaml:"inhibit_rules,omitempty"`
+}
+
+type AlertmanagerGlobal struct {
+	SlackApiUrl string `yaml:"slack_api_url,omitempty"`
+}
+
+type AlertmanagerRoute struct {
+	Receiver string              `yaml:"receiver,omitempty"`
+	GroupBy  []string            `yaml:"group_by,omitempty"`
+	Matchers []string            `yaml:"matchers,omitempty"`
+	Continue bool                `yaml:"continue,omitempty"`
+	Routes   []AlertmanagerRoute `yaml:"routes,omitempty"`
+}
+
+type AlertmanagerReceiver struct {
+	Name             string                        `yaml:"name"`
+	WebhookConfigs   []AlertmanagerWebhookConfig   `yaml:"webhook_configs,omitempty"`
+	EmailConfigs     []AlertmanagerEmailConfig     `yaml:"email_configs,omitempty"`
+	SlackConfigs     []AlertmanagerSlackConfig     `yaml:"slack_configs,omitempty"`
+	PagerdutyConfigs []AlertmanagerPagerdutyConfig `yaml:"pagerduty_configs,omitempty"`
+}
+
+type AlertmanagerWebhookConfig struct {
+	SendResolved bool   `yaml:"send_resolved"`
+	Url          string `yaml:"url"`
+}
+
+type AlertmanagerEmailConfig struct {
+	SendResolved bool   `yaml:"send_resolved"`
+	To           string `yaml:"to"`
+}
+
+type AlertmanagerSlackConfig struct {
+	SendResolved bool   `yaml:"send_resolved"`
+	Channel      string `yaml:"channel"`
+}
+
+type AlertmanagerPagerdutyConfig struct {
+	SendResolved bool   `yaml:"send_resolved"`
+	RoutingKey   string `yaml:"routing_key"`
+}
+
+type AlertmanagerInhibitRule struct {
+	SourceMatchers []string `yaml:"source_matchers"`
+	TargetMatchers []string `yaml:"target_matchers"`
+	Equal          []string `yaml:"equal"`
+}
+
+// RenderAlertmanagerConfig renders the alertmanager route tree, receivers and inhibit rules of the SLO alert policies.
+// Routes match the labels of the prometheus_rules alerts: service, then SLO, then one route per alert policy. Slack
+// targets are only routed when the slack webhook url is set.
+func (d *OpenSloDataSource) RenderAlertmanagerConfig(diagnostics *diag.Diagnostics) error {
+	slackApiUrl := d.Alertmanager_slack_api_url.ValueString()
+	config := AlertmanagerConfig{
+		Route: AlertmanagerRoute{
+			Receiver: ALERTMANAGER_BLACKHOLE_RECEIVER,
+			GroupBy:  alertmanagerGroupBy,
+		},
+		Receivers: []AlertmanagerReceiver{{Name: ALERTMANAGER_BLACKHOLE_RECEIVER}},
+	}
+	if slackApiUrl != "" {
+		config.Global = &AlertmanagerGlobal{SlackApiUrl: slackApiUrl}
+	}
+
+	receivers := map[string]bool{}
+	serviceRoutes := map[string]int{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		if len(slo.AlertPolicies) == 0 {
+			continue
+		}
+
+		sloRoute := AlertmanagerRoute{Matchers: []string{alertmanagerMatcher(PROMETHEUS_LABEL_SLO, sloName)}}
+		for i, policy := range slo.AlertPolicies {
+			receiverName := AlertPolicyName(sloName, i, policy)
+			sloRoute.Routes = append(sloRoute.Routes, AlertmanagerPolicyRoutes(receiverName, policy)...)
+			if !receivers[receiverName] {
+				receivers[receiverName] = true
+				config.Receivers = append(config.Receivers, AlertmanagerReceiverOf(receiverName, policy, slackApiUrl != "", diagnostics))
+			}
+		}
+		if SloAlertsWhenNoData(slo) {
+			config.InhibitRules = append(config.InhibitRules, AlertmanagerInhibitRule{
+				SourceMatchers: []string{alertmanagerMatcher("alertname", PROMETHEUS_ALERT_NO_DATA), alertmanagerMatcher(PROMETHEUS_LABEL_SLO, sloName)},
+				TargetMatchers: []string{alertmanagerMatcher("alertname", PROMETHEUS_ALERT_BURN_RATE), alertmanagerMatcher(PROMETHEUS_LABEL_SLO, sloName)},
+				Equal:          []string{PROMETHEUS_LABEL_SLO, PROMETHEUS_LABEL_OBJECTIVE},
+			})
+		}
+
+		if slo.ServiceRef == "" {
+			config.Route.Routes = append(config.Route.Routes, sloRoute)
+			continue
+		}
+		index, ok := serviceRoutes[slo.ServiceRef]
+		if !ok {
+			index = len(config.Route.Routes)
+			serviceRoutes[slo.ServiceRef] = index
+			config.Route.Routes = append(config.Route.Routes, AlertmanagerRoute{
+				Matchers: []string{alertmanagerMatcher(PROMETHEUS_LABEL_SERVICE, slo.ServiceRef)},
+			})
+		}
+		config.Route.Routes[index].Routes = append(config.Route.Routes[index].Routes, sloRoute)
+	}
+
+	alertmanagerConfig, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	d.Alertmanager_config = string(alertmanagerConfig)
+	return nil
+}
+
+// AlertPolicyName returns the name of the referenced alert policy, or "slo/index" for inline policies, as SLOs can
+// have inline policies of the same name. It is the openslo_alert_policy label of the burn rate alerts and the name of
+// the alertmanager receiver, so referenced policies have one receiver shared by their SLOs.
+func AlertPolicyName(sloName string, index int, policy AlertPolicyModel) string {
+	if policy.AlertPolicyRef != "" {
+		return policy.AlertPolicyRef
+	}
+	return fmt.Sprintf("%s/%s", sloName, strconv.Itoa(index))
+}
+
+// AlertmanagerPolicyRoutes returns the routes sending the SLO alerts to the policy receiver. Burn rate alerts are
+// matched on the severities of the policy conditions, and on the policy when it has burnrate conditions. No data
+// alerts are only routed when the policy alerts when there is no data.
+func AlertmanagerPolicyRoutes(receiverName string, policy AlertPolicyModel) []AlertmanagerRoute {
+	matchers := []string{alertmanagerMatcher("alertname", PROMETHEUS_ALERT_BURN_RATE)}
+	var severities []string
+	hasBurnRate := false
+	for _, condition := range policy.Conditions {
+		if condition.Severity != "" && !containsString(severities, condition.Severity) {
+			severities = append(severities, condition.Severity)
+		}
+		if strings.EqualFold(condition.Condition.Kind, ALERT_CONDITION_KIND_BURN_RATE) {
+			hasBurnRate = true
+		}
+	}
+	if hasBurnRate {
+		matchers = append(matchers, alertmanagerMatcher(PROMETHEUS_LABEL_ALERT_POLICY, receiverName))
+	}
+	if len(severities) > 0 {
+		sort.Strings(severities)
+		quoted := make([]string, len(severities))
+		for i, severity := range severities {
+			quoted[i] = regexp.QuoteMeta(severity)
+		}
+		matchers = append(matchers, fmt.Sprintf("severity=~%q", strings.Join(quoted, "|")))
+	}
+
+	routes := []AlertmanagerRoute{{Receiver: receiverName, Matchers: matchers, Continue: true}}
+	if policy.AlertWhenNoData {
+		routes = append(routes, AlertmanagerRoute{
+			Receiver: receiverName,
+			Matchers: []string{alertmanagerMatcher("alertname", PROMETHEUS_ALERT_NO_DATA)},
+			Continue: true,
+		})
+	}
+	return routes
+}
+
+// AlertmanagerReceiverOf returns the receiver of the policy notification targets. Targets are read as
+// an http(s) url (webhook), an email address or mailto: url, a slack channel (#channel or slack:#channel)
+// or a pagerduty:<routing key>. Other targets, and slack channels without a slack webhook url, produce a warning.
+func AlertmanagerReceiverOf(receiverName string, policy AlertPolicyModel, slack bool, diagnostics *diag.Diagnostics) AlertmanagerReceiver {
+	receiver := AlertmanagerReceiver{Name: receiverName}
+	sendResolved := policy.AlertWhenResolved
+	for _, notificationTarget := range policy.NotificationTargets {
+		target := strings.TrimSpace(notificationTarget.Target)
+		switch {
+		case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+			receiver.WebhookConfigs = append(receiver.WebhookConfigs, AlertmanagerWebhookConfig{SendResolved: sendResolved, Url: target})
+		case (strings.HasPrefix(target, "slack:") || strings.HasPrefix(target, "#")) && !slack:
+			diagnostics.AddWarning("Cannot route alert notification target, skipping",
+				fmt.Sprintf("alert policy %s target %q: slack channels need the alertmanager_slack_api_url webhook url", receiverName, target))
+		case strings.HasPrefix(target, "slack:") || strings.HasPrefix(target, "#"):
+			receiver.SlackConfigs = append(receiver.SlackConfigs, AlertmanagerSlackConfig{SendResolved: sendResolved, Channel: strings.TrimPrefix(target, "slack:")})
+		case strings.HasPrefix(target, "pagerduty:"):
+			receiver.PagerdutyConfigs = append(receiver.PagerdutyConfigs, AlertmanagerPagerdutyConfig{SendResolved: sendResolved, RoutingKey: strings.TrimPrefix(target, "pagerduty:")})
+		case isEmailAddress(strings.TrimPrefix(target, "mailto:")):
+			receiver.EmailConfigs = append(receiver.EmailConfigs, AlertmanagerEmailConfig{SendResolved: sendResolved, To: strings.TrimPrefix(target, "mailto:")})
+		default:
+			diagnostics.AddWarning("Cannot route alert notification target, skipping",
+				fmt.Sprintf("alert policy %s target %q: expected an http(s) url, an email address, a #slack-channel or a pagerduty:<routing key>", receiverName, target))
+		}
+	}
+	return receiver
+}
+
+func isEmailAddress(address string) bool {
+	parsed, err := mail.ParseAddress(address)
+	return err == nil && parsed.Address == address
+}
+
+func alertmanagerMatcher(label string, value string) string {
+	return fmt.Sprintf("%s=%q", label, value)
+}
@@ -0,0 +1,358 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const alertmanagerYamlSpec = `
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+spec:
+  description: Checkout service
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total{code=~"5.."}
+    total:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo/v1
+kind: AlertCondition
+metadata:
+  name: fast-burn
+spec:
+  severity: page
+  condition:
+    kind: burnrate
+    op: gt
+    threshold: 14.4
+    lookbackWindow: 1h
+---
+apiVersion: openslo/v1
+kind: AlertNotificationTarget
+metadata:
+  name: on-call-mail
+spec:
+  target: oncall@example.com
+---
+apiVersion: openslo/v1
+kind: AlertPolicy
+metadata:
+  name: on-call
+spec:
+  alertWhenNoData: true
+  alertWhenResolved: true
+  conditions:
+  - conditionRef: fast-burn
+  notificationTargets:
+  - targetRef: on-call-mail
+  - target: "#checkout-alerts"
+  - target: carrier pigeon
+---
+apiVersion: openslo/v1
+kind: AlertPolicy
+metadata:
+  name: tickets
+spec:
+  conditions:
+  - kind: AlertCondition
+    metadata:
+      name: slow-burn
+    spec:
+      severity: ticket
+      condition:
+        kind: threshold
+        op: gt
+        threshold: 1
+        lookbackWindow: 1d
+  notificationTargets:
+  - target: https://tickets.example.com/hook
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+spec:
+  service: checkout
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Available
+    target: 0.999
+  alertPolicies:
+  - alertPolicyRef: on-call
+  - alertPolicyRef: tickets
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: unalerted
+spec:
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+`
+
+func TestOpenSLOAlertmanagerConfig_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{Alertmanager_slack_api_url: types.StringValue("https://hooks.slack.com/services/T0/B0/X")}
+	err := openslo.GetOpenSloData(alertmanagerYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	var config AlertmanagerConfig
+	err = yaml.Unmarshal([]byte(openslo.Alertmanager_config), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := AlertmanagerConfig{
+		Global: &AlertmanagerGlobal{SlackApiUrl: "https://hooks.slack.com/services/T0/B0/X"},
+		Route: AlertmanagerRoute{
+			Receiver: "openslo-blackhole",
+			GroupBy:  []string{"alertname", "openslo_slo", "openslo_objective"},
+			Routes: []AlertmanagerRoute{
+				{
+					Matchers: []string{`openslo_service="checkout"`},
+					Routes: []AlertmanagerRoute{
+						{
+							Matchers: []string{`openslo_slo="checkout-availability"`},
+							Routes: []AlertmanagerRoute{
+								{
+									Receiver: "on-call",
+									Matchers: []string{`alertname="OpenSLOErrorBudgetBurn"`, `openslo_alert_policy="on-call"`, `severity=~"page"`},
+									Continue: true,
+								},
+								{
+									Receiver: "on-call",
+									Matchers: []string{`alertname="OpenSLONoData"`},
+									Continue: true,
+								},
+								{
+									Receiver: "tickets",
+									Matchers: []string{`alertname="OpenSLOErrorBudgetBurn"`, `severity=~"ticket"`},
+									Continue: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Receivers: []AlertmanagerReceiver{
+			{Name: "openslo-blackhole"},
+			{
+				Name:         "on-call",
+				EmailConfigs: []AlertmanagerEmailConfig{{SendResolved: true, To: "oncall@example.com"}},
+				SlackConfigs: []AlertmanagerSlackConfig{{SendResolved: true, Channel: "#checkout-alerts"}},
+			},
+			{
+				Name:           "tickets",
+				WebhookConfigs: []AlertmanagerWebhookConfig{{SendResolved: false, Url: "https://tickets.example.com/hook"}},
+			},
+		},
+		InhibitRules: []AlertmanagerInhibitRule{
+			{
+				SourceMatchers: []string{`alertname="OpenSLONoData"`, `openslo_slo="checkout-availability"`},
+				TargetMatchers: []string{`alertname="OpenSLOErrorBudgetBurn"`, `openslo_slo="checkout-availability"`},
+				Equal:          []string{"openslo_slo", "openslo_objective"},
+			},
+		},
+	}
+	diff := deep.Equal(config, expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and unknown targets are reported
+	warnings := warningsWithSummary(diagnostics, "Cannot route alert notification target, skipping")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), `alert policy on-call target "carrier pigeon"`) {
+		t.Errorf("Expected a warning naming the target, but got %v", warnings)
+	}
+
+	// and the no data alert is rendered for the policy
+	if !strings.Contains(openslo.Prometheus_rules["checkout-availability"], "alert: OpenSLONoData") {
+		t.Errorf("Expected a no data alert, but got %s", openslo.Prometheus_rules["checkout-availability"])
+	}
+	if strings.Contains(openslo.Prometheus_rules["unalerted"], "alert: OpenSLONoData") {
+		t.Error("Expected no data alert only for policies alerting when there is no data")
+	}
+
+	// and
+	openSloState(t, &openslo)
+}
+
+const alertmanagerInlinePoliciesYamlSpec = `
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total{code=~"5.."}
+    total:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout
+spec:
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+  alertPolicies:
+  - kind: AlertPolicy
+    spec:
+      conditions:
+      - kind: AlertCondition
+        spec:
+          severity: page
+          condition:
+            kind: burnrate
+            threshold: 14.4
+            lookbackWindow: 1h
+      notificationTargets:
+      - target: "#checkout-alerts"
+  - kind: AlertPolicy
+    metadata:
+      name: tickets
+    spec:
+      conditions:
+      - kind: AlertCondition
+        spec:
+          severity: ticket
+          condition:
+            kind: burnrate
+            threshold: 1
+            lookbackWindow: 3d
+      notificationTargets:
+      - target: https://checkout.example.com/hook
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: payments
+spec:
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+  alertPolicies:
+  - kind: AlertPolicy
+    metadata:
+      name: tickets
+    spec:
+      conditions:
+      - kind: AlertCondition
+        spec:
+          severity: ticket
+          condition:
+            kind: burnrate
+            threshold: 1
+            lookbackWindow: 3d
+      notificationTargets:
+      - target: https://payments.example.com/hook
+`
+
+func TestOpenSLOAlertmanagerConfig_shouldbeValid_inlinePolicies(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(alertmanagerInlinePoliciesYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	var config AlertmanagerConfig
+	err = yaml.Unmarshal([]byte(openslo.Alertmanager_config), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and the alerts of the unnamed policy carry the label its route matches
+	var rules PrometheusRuleFile
+	err = yaml.Unmarshal([]byte(openslo.Prometheus_rules["checkout"]), &rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pageAlert PrometheusRule
+	for _, group := range rules.Groups {
+		for _, rule := range group.Rules {
+			if rule.Alert == PROMETHEUS_ALERT_BURN_RATE && rule.Labels["severity"] == "page" {
+				pageAlert = rule
+			}
+		}
+	}
+	policyMatcher := alertmanagerMatcher(PROMETHEUS_LABEL_ALERT_POLICY, pageAlert.Labels[PROMETHEUS_LABEL_ALERT_POLICY])
+	checkoutRoutes := config.Route.Routes[0].Routes
+	if len(checkoutRoutes) != 2 || checkoutRoutes[0].Receiver != "checkout/0" || !containsString(checkoutRoutes[0].Matchers, policyMatcher) {
+		t.Errorf("Expected the checkout/0 route to match %s, but got %+v", policyMatcher, checkoutRoutes)
+	}
+
+	// and inline policies, named or not, get a receiver per SLO named by their index
+	diff := deep.Equal(config.Receivers, []AlertmanagerReceiver{
+		{Name: "openslo-blackhole"},
+		{Name: "checkout/0"},
+		{
+			Name:           "checkout/1",
+			WebhookConfigs: []AlertmanagerWebhookConfig{{Url: "https://checkout.example.com/hook"}},
+		},
+		{
+			Name:           "payments/0",
+			WebhookConfigs: []AlertmanagerWebhookConfig{{Url: "https://payments.example.com/hook"}},
+		},
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+	if config.Route.Routes[1].Routes[0].Receiver != "payments/0" {
+		t.Errorf("Expected the payments route to use its own receiver, but got %+v", config.Route.Routes[1])
+	}
+
+	// and slack channels are skipped without a slack webhook url
+	if config.Global != nil {
+		t.Errorf("Expected no global config, but got %+v", config.Global)
+	}
+	warnings := warningsWithSummary(diagnostics, "Cannot route alert notification target, skipping")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "alertmanager_slack_api_url") {
+		t.Errorf("Expected a warning naming alertmanager_slack_api_url, but got %v", warnings)
+	}
+}
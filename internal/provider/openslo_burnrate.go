@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	ALERT_CONDITION_KIND_BURN_RATE = "burnrate"
+	DEFAULT_BURN_RATE_OP           = "gt"
+	// The short window is 1/12 of the long one, as recommended by the Google SRE workbook
+	BURN_RATE_SHORT_WINDOW_RATIO = 12
+)
+
+// ComputeBurnRateAlerts builds the burn_rate_alerts map, with one entry per objective and burnrate alert condition of every SLO
+func (d *OpenSloDataSource) ComputeBurnRateAlerts(diagnostics *diag.Diagnostics) {
+	d.Burn_rate_alerts = map[string]BurnRateAlertModel{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			alerts, err := ObjectiveBurnRateAlerts(sloName, slo, key, slo.Objectives[i])
+			if err != nil {
+				diagnostics.AddWarning("Cannot compute burn rate alerts, skipping", fmt.Sprintf("objective %s: %s", key, err.Error()))
+				continue
+			}
+			for _, alert := range alerts {
+				d.Burn_rate_alerts[BurnRateAlertKey(alert)] = alert
+			}
+		}
+	}
+}
+
+// BurnRateAlertKey returns the key of a burn rate alert, "objective/alertPolicy/alertCondition"
+func BurnRateAlertKey(alert BurnRateAlertModel) string {
+	return fmt.Sprintf("%s/%s/%s", alert.Objective, alert.AlertPolicy, alert.AlertCondition)
+}
+
+// ObjectiveBurnRateAlerts returns the burn rate alerts of an objective, one per burnrate condition of the SLO alert policies.
+// Policies are named by AlertPolicyName, conditions without a name are named after their index.
+func ObjectiveBurnRateAlerts(sloName string, slo SLOModel, key string, objective ObjectiveModel) ([]BurnRateAlertModel, error) {
+	var alerts []BurnRateAlertModel
+	for i, policy := range slo.AlertPolicies {
+		policyName := AlertPolicyName(sloName, i, policy)
+		for j, condition := range policy.Conditions {
+			if !strings.EqualFold(condition.Condition.Kind, ALERT_CONDITION_KIND_BURN_RATE) {
+				continue
+			}
+			conditionName := condition.Metadata.Name
+			if conditionName == "" {
+				conditionName = strconv.Itoa(j)
+			}
+
+			alert, err := NewBurnRateAlert(ObjectiveTarget(objective), SloTimeWindow(slo), condition.Condition)
+			if err != nil {
+				return nil, fmt.Errorf("alert policy %s condition %s: %w", policyName, conditionName, err)
+			}
+			alert.Slo = sloName
+			alert.Objective = key
+			alert.AlertPolicy = policyName
+			alert.AlertCondition = conditionName
+			alert.Severity = condition.Severity
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+// NewBurnRateAlert derives the windows and thresholds of a burnrate condition, for an objective target over the SLO time window.
+// The alert fires when the error ratio over both the lookback window and its short window exceeds threshold times the error budget.
+func NewBurnRateAlert(target float64, timeWindow string, condition AlertConditionModelCondition) (BurnRateAlertModel, error) {
+	if target <= 0 || target >= 1 {
+		return BurnRateAlertModel{}, fmt.Errorf("target must be between 0 and 1, got %v", target)
+	}
+	period, err := ParseOpenSloDuration(timeWindow)
+	if err != nil {
+		return BurnRateAlertModel{}, fmt.Errorf("timeWindow: %w", err)
+	}
+	if condition.Threshold <= 0 {
+		return BurnRateAlertModel{}, fmt.Errorf("threshold must be a positive burn rate, got %v", condition.Threshold)
+	}
+	longWindow, err := ParseOpenSloDuration(condition.LookbackWindow)
+	if err != nil {
+		return BurnRateAlertModel{}, fmt.Errorf("lookbackWindow: %w", err)
+	}
+	if longWindow > period {
+		return BurnRateAlertModel{}, fmt.Errorf("lookbackWindow %s is longer than the SLO time window %s", condition.LookbackWindow, timeWindow)
+	}
+	shortWindow := (longWindow / BURN_RATE_SHORT_WINDOW_RATIO).Truncate(time.Second)
+	if shortWindow < time.Minute {
+		return BurnRateAlertModel{}, fmt.Errorf("lookbackWindow must be at least %dm, got %s", BURN_RATE_SHORT_WINDOW_RATIO, condition.LookbackWindow)
+	}
+	alertAfter := ""
+	if condition.AlertAfter != "" {
+		duration, err := ParseOpenSloDuration(condition.AlertAfter)
+		if err != nil {
+			return BurnRateAlertModel{}, fmt.Errorf("alertAfter: %w", err)
+		}
+		alertAfter = FormatPrometheusDuration(duration)
+	}
+	op := condition.Op
+	if op == "" {
+		op = DEFAULT_BURN_RATE_OP
+	}
+	opSymbol, ok := prometheusThresholdOps[op]
+	if !ok {
+		return BurnRateAlertModel{}, fmt.Errorf("op must be one of lt, lte, gt or gte, got %q", condition.Op)
+	}
+
+	errorBudget := roundFloat(1 - target)
+	errorRateThreshold := roundFloat(condition.Threshold * errorBudget)
+	return BurnRateAlertModel{
+		Op:                 op,
+		BurnRate:           condition.Threshold,
+		Target:             target,
+		ErrorBudget:        errorBudget,
+		ErrorRateThreshold: errorRateThreshold,
+		TimeWindow:         FormatPrometheusDuration(period),
+		LongWindow:         FormatPrometheusDuration(longWindow),
+		ShortWindow:        FormatPrometheusDuration(shortWindow),
+		AlertAfter:         alertAfter,
+		BudgetConsumed:     roundFloat(condition.Threshold * float64(longWindow) / float64(period)),
+		Expression: fmt.Sprintf("error_ratio(%s) %s %s and error_ratio(%s) %s %s",
+			FormatPrometheusDuration(longWindow), opSymbol, formatPrometheusFloat(errorRateThreshold),
+			FormatPrometheusDuration(shortWindow), opSymbol, formatPrometheusFloat(errorRateThreshold)),
+	}, nil
+}
@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const burnRateYamlSpec = `
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total{code=~"5.."}
+    total:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo/v1
+kind: AlertCondition
+metadata:
+  name: fast-burn
+spec:
+  severity: page
+  condition:
+    kind: burnrate
+    op: gte
+    threshold: 14.4
+    lookbackWindow: 1h
+    alertAfter: 2m
+---
+apiVersion: openslo/v1
+kind: AlertCondition
+metadata:
+  name: slow-burn
+spec:
+  severity: ticket
+  condition:
+    kind: burnrate
+    op: gt
+    threshold: 1
+    lookbackWindow: 3d
+---
+apiVersion: openslo/v1
+kind: AlertPolicy
+metadata:
+  name: on-call
+spec:
+  conditions:
+  - conditionRef: fast-burn
+  - conditionRef: slow-burn
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout
+spec:
+  indicatorRef: errors
+  timeWindow:
+  - duration: 30d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Available
+    target: 0.999
+  alertPolicies:
+  - alertPolicyRef: on-call
+`
+
+func TestOpenSLOBurnRateAlerts_shouldMatch_sreWorkbook(t *testing.T) {
+	// given the SRE workbook alerting on a 99.9% objective over 30 days
+	cases := []struct {
+		burnRate       float64
+		lookbackWindow string
+		shortWindow    string
+		errorRate      float64
+		budgetConsumed float64
+	}{
+		{burnRate: 14.4, lookbackWindow: "1h", shortWindow: "5m", errorRate: 0.0144, budgetConsumed: 0.02},
+		{burnRate: 6, lookbackWindow: "6h", shortWindow: "30m", errorRate: 0.006, budgetConsumed: 0.05},
+		{burnRate: 3, lookbackWindow: "1d", shortWindow: "2h", errorRate: 0.003, budgetConsumed: 0.1},
+		{burnRate: 1, lookbackWindow: "3d", shortWindow: "6h", errorRate: 0.001, budgetConsumed: 0.1},
+	}
+
+	for _, c := range cases {
+		// when
+		alert, err := NewBurnRateAlert(0.999, "30d", AlertConditionModelCondition{
+			Kind:           "burnrate",
+			Threshold:      c.burnRate,
+			LookbackWindow: c.lookbackWindow,
+		})
+
+		// then
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// and
+		if alert.LongWindow != c.lookbackWindow || alert.ShortWindow != c.shortWindow {
+			t.Errorf("Expected %s/%s windows, but got %s/%s", c.lookbackWindow, c.shortWindow, alert.LongWindow, alert.ShortWindow)
+		}
+		if alert.ErrorBudget != 0.001 || alert.ErrorRateThreshold != c.errorRate || alert.BudgetConsumed != c.budgetConsumed {
+			t.Errorf("Expected %v error rate consuming %v of the budget, but got %+v", c.errorRate, c.budgetConsumed, alert)
+		}
+	}
+}
+
+func TestOpenSLOBurnRateAlerts_shouldbeValid_fromAlertPolicies(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(burnRateYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Burn_rate_alerts), []string{
+		"checkout/Available/on-call/fast-burn",
+		"checkout/Available/on-call/slow-burn",
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	expected := BurnRateAlertModel{
+		Slo:                "checkout",
+		Objective:          "checkout/Available",
+		AlertPolicy:        "on-call",
+		AlertCondition:     "fast-burn",
+		Severity:           "page",
+		Op:                 "gte",
+		BurnRate:           14.4,
+		Target:             0.999,
+		ErrorBudget:        0.001,
+		ErrorRateThreshold: 0.0144,
+		TimeWindow:         "30d",
+		LongWindow:         "1h",
+		ShortWindow:        "5m",
+		AlertAfter:         "2m",
+		BudgetConsumed:     0.02,
+		Expression:         "error_ratio(1h) >= 0.0144 and error_ratio(5m) >= 0.0144",
+	}
+	diff = deep.Equal(openslo.Burn_rate_alerts["checkout/Available/on-call/fast-burn"], expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and prometheus alerts use the conditions instead of the default windows
+	var rules PrometheusRuleFile
+	err = yaml.Unmarshal([]byte(openslo.Prometheus_rules["checkout"]), &rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts := rules.Groups[2].Rules
+	if len(alerts) != 2 {
+		t.Fatalf("Expected 2 alerts, but got %+v", alerts)
+	}
+	if alerts[0].For != "2m" || alerts[0].Labels["severity"] != "page" || alerts[0].Labels[PROMETHEUS_LABEL_ALERT_POLICY] != "on-call" {
+		t.Errorf("Expected a page alert of the on-call policy after 2m, but got %+v", alerts[0])
+	}
+	if !strings.Contains(alerts[0].Expr, `slo:sli_error:ratio_rate5m{openslo_slo="checkout", openslo_objective="Available"} >= 0.0144`) {
+		t.Errorf("Expected a 14.4 burn rate on 5m, but got %s", alerts[0].Expr)
+	}
+	var records []string
+	for _, rule := range rules.Groups[0].Rules {
+		records = append(records, rule.Record)
+	}
+	diff = deep.Equal(records, []string{
+		"slo:sli_error:ratio_rate5m",
+		"slo:sli_error:ratio_rate1h",
+		"slo:sli_error:ratio_rate6h",
+		"slo:sli_error:ratio_rate3d",
+		"slo:sli_error:ratio_rate30d",
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	openSloState(t, &openslo)
+}
+
+func TestOpenSLOBurnRateAlerts_shouldbeWarning_badCondition(t *testing.T) {
+	// given
+	yamlSpec := strings.Replace(burnRateYamlSpec, "lookbackWindow: 3d", "lookbackWindow: 60d", 1)
+
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(yamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	if len(openslo.Burn_rate_alerts) != 0 {
+		t.Errorf("Expected objective to be skipped, but got %v", sortedKeys(openslo.Burn_rate_alerts))
+	}
+	if len(diagnostics.Warnings()) == 0 || !strings.Contains(diagnostics.Warnings()[0].Detail(), "on-call condition slow-burn: lookbackWindow 60d") {
+		t.Errorf("Expected warning to name the condition, but got %v", diagnostics.Warnings())
+	}
+}
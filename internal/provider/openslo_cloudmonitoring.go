@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Metric source types of Google Cloud Monitoring, compared without case, dashes and underscores
+var cloudMonitoringSourceTypes = []string{"cloudmonitoring", "googlecloudmonitoring", "stackdriver", "gcm"}
+
+// Cloud Monitoring calendar periods, by (approximated) duration
+var cloudMonitoringCalendarPeriods = map[time.Duration]string{
+	DAY:       "DAY",
+	7 * DAY:   "WEEK",
+	14 * DAY:  "FORTNIGHT",
+	30 * DAY:  "MONTH",
+	90 * DAY:  "QUARTER",
+	180 * DAY: "HALF",
+	365 * DAY: "YEAR",
+}
+
+// Cloud Monitoring rolling periods are whole days, up to 30
+const CLOUD_MONITORING_MAX_ROLLING_PERIOD = 30 * DAY
+
+// CloudMonitoringSlo is the projects.services.serviceLevelObjectives resource
+type CloudMonitoringSlo struct {
+	DisplayName           string                               `json:"displayName"`
+	Goal                  float64                              `json:"goal"`
+	RollingPeriod         string                               `json:"rollingPeriod,omitempty"`
+	CalendarPeriod        string                               `json:"calendarPeriod,omitempty"`
+	ServiceLevelIndicator CloudMonitoringServiceLevelIndicator `json:"serviceLevelIndicator"`
+	UserLabels            map[string]string                    `json:"userLabels,omitempty"`
+}
+
+type CloudMonitoringServiceLevelIndicator struct {
+	RequestBased CloudMonitoringRequestBasedSli `json:"requestBased"`
+}
+
+type CloudMonitoringRequestBasedSli struct {
+	GoodTotalRatio  *CloudMonitoringTimeSeriesRatio `json:"goodTotalRatio,omitempty"`
+	DistributionCut *CloudMonitoringDistributionCut `json:"distributionCut,omitempty"`
+}
+
+type CloudMonitoringTimeSeriesRatio struct {
+	GoodServiceFilter  string `json:"goodServiceFilter,omitempty"`
+	BadServiceFilter   string `json:"badServiceFilter,omitempty"`
+	TotalServiceFilter string `json:"totalServiceFilter,omitempty"`
+}
+
+type CloudMonitoringDistributionCut struct {
+	DistributionFilter string               `json:"distributionFilter"`
+	Range              CloudMonitoringRange `json:"range"`
+}
+
+// CloudMonitoringRange is a closed range, an unset bound is infinite
+type CloudMonitoringRange struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// RenderCloudMonitoringSlos renders the Cloud Monitoring SLO (json) of every cloud monitoring backed objective, keyed by objective
+func (d *OpenSloDataSource) RenderCloudMonitoringSlos(diagnostics *diag.Diagnostics) error {
+	d.Cloud_monitoring_slos = map[string]string{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			objective := slo.Objectives[i]
+			indicator := ObjectiveIndicator(slo, objective)
+			if !IsCloudMonitoringIndicator(indicator) {
+				continue
+			}
+
+			cloudMonitoringSlo, err := CloudMonitoringSloOf(sloName, key, slo, objective, indicator)
+			if err != nil {
+				diagnostics.AddWarning("Cannot render cloud monitoring SLO, skipping", fmt.Sprintf("objective %s: %s", key, err.Error()))
+				continue
+			}
+			sloJson, err := json.MarshalIndent(cloudMonitoringSlo, "", "  ")
+			if err != nil {
+				return fmt.Errorf("objective %s: %w", key, err)
+			}
+			d.Cloud_monitoring_slos[key] = string(sloJson)
+		}
+	}
+	return nil
+}
+
+// IsCloudMonitoringIndicator returns true if every metric source of the SLI is a Google Cloud Monitoring one
+func IsCloudMonitoringIndicator(sli SLIModel) bool {
+	sourceType := strings.NewReplacer("-", "", "_", "").Replace(indicatorMetricSourceType(sli))
+	return containsString(cloudMonitoringSourceTypes, sourceType)
+}
+
+// CloudMonitoringSloOf returns the Cloud Monitoring SLO of an objective. Ratio SLIs become a goodTotalRatio
+// and threshold SLIs a distributionCut, both read the monitoring filter from metricSource.spec.filter.
+func CloudMonitoringSloOf(sloName string, key string, slo SLOModel, objective ObjectiveModel, indicator SLIModel) (*CloudMonitoringSlo, error) {
+	target := ObjectiveTarget(objective)
+	if target <= 0 || target >= 1 {
+		return nil, fmt.Errorf("target must be between 0 and 1, got %v", target)
+	}
+	timeWindow := TimeWindowModel{Duration: DEFAULT_SLO_TIME_WINDOW, IsRolling: true}
+	if len(slo.TimeWindow) > 0 {
+		timeWindow = slo.TimeWindow[0]
+	}
+	rollingPeriod, calendarPeriod, err := CloudMonitoringPeriod(timeWindow)
+	if err != nil {
+		return nil, err
+	}
+	sli, err := CloudMonitoringSli(indicator, objective)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := slo.Metadata.DisplayName
+	if displayName == "" {
+		displayName = sloName
+	}
+	if len(slo.Objectives) > 1 {
+		displayName = fmt.Sprintf("%s - %s", displayName, strings.TrimPrefix(key, sloName+"/"))
+	}
+	return &CloudMonitoringSlo{
+		DisplayName:           displayName,
+		Goal:                  roundFloat(target),
+		RollingPeriod:         rollingPeriod,
+		CalendarPeriod:        calendarPeriod,
+		ServiceLevelIndicator: *sli,
+		UserLabels:            slo.Metadata.Labels,
+	}, nil
+}
+
+// CloudMonitoringPeriod maps a time window to a rolling period (in seconds) or to a calendar period
+func CloudMonitoringPeriod(timeWindow TimeWindowModel) (rollingPeriod string, calendarPeriod string, err error) {
+	duration, err := ParseOpenSloDuration(timeWindow.Duration)
+	if err != nil {
+		return "", "", fmt.Errorf("timeWindow: %w", err)
+	}
+	if !timeWindow.IsRolling {
+		period, ok := cloudMonitoringCalendarPeriods[duration]
+		if !ok {
+			return "", "", fmt.Errorf("calendar timeWindow %s is not supported by cloud monitoring, expected a day, week, fortnight, month, quarter, half or year", timeWindow.Duration)
+		}
+		return "", period, nil
+	}
+	if duration%DAY != 0 || duration > CLOUD_MONITORING_MAX_ROLLING_PERIOD {
+		return "", "", fmt.Errorf("rolling timeWindow %s is not supported by cloud monitoring, expected whole days up to 30d", timeWindow.Duration)
+	}
+	return fmt.Sprintf("%ds", int64(duration/time.Second)), "", nil
+}
+
+// CloudMonitoringSli returns the request based SLI of a ratio or threshold indicator
+func CloudMonitoringSli(sli SLIModel, objective ObjectiveModel) (*CloudMonitoringServiceLevelIndicator, error) {
+	if isMetricSourceSet(sli.ThresholdMetric.MetricSource) {
+		filter, err := cloudMonitoringFilter(sli.ThresholdMetric.MetricSource)
+		if err != nil {
+			return nil, fmt.Errorf("thresholdMetric: %w", err)
+		}
+		value := objective.Value
+		cut := CloudMonitoringDistributionCut{DistributionFilter: filter}
+		switch objective.Op {
+		case "lt", "lte":
+			cut.Range.Max = &value
+		case "gt", "gte":
+			cut.Range.Min = &value
+		default:
+			return nil, fmt.Errorf("thresholdMetric needs an objective op of lt, lte, gt or gte, got %q", objective.Op)
+		}
+		return &CloudMonitoringServiceLevelIndicator{RequestBased: CloudMonitoringRequestBasedSli{DistributionCut: &cut}}, nil
+	}
+
+	ratio := sli.RatioMetric
+	if ratio.RawType != "" || isMetricSourceSet(ratio.Raw.MetricSource) {
+		return nil, fmt.Errorf("ratioMetric.raw is not supported by cloud monitoring")
+	}
+	filters := CloudMonitoringTimeSeriesRatio{}
+	for _, metric := range []struct {
+		name         string
+		metricSource MetricSource
+		filter       *string
+	}{
+		{"good", ratio.Good.MetricSource, &filters.GoodServiceFilter},
+		{"bad", ratio.Bad.MetricSource, &filters.BadServiceFilter},
+		{"total", ratio.Total.MetricSource, &filters.TotalServiceFilter},
+	} {
+		if !isMetricSourceSet(metric.metricSource) {
+			continue
+		}
+		filter, err := cloudMonitoringFilter(metric.metricSource)
+		if err != nil {
+			return nil, fmt.Errorf("ratioMetric.%s: %w", metric.name, err)
+		}
+		*metric.filter = filter
+	}
+	// Cloud monitoring needs exactly two of the three filters
+	count := 0
+	for _, filter := range []string{filters.GoodServiceFilter, filters.BadServiceFilter, filters.TotalServiceFilter} {
+		if filter != "" {
+			count++
+		}
+	}
+	if count != 2 {
+		return nil, fmt.Errorf("ratioMetric needs exactly two of good, bad and total metrics for cloud monitoring")
+	}
+	return &CloudMonitoringServiceLevelIndicator{RequestBased: CloudMonitoringRequestBasedSli{GoodTotalRatio: &filters}}, nil
+}
+
+// cloudMonitoringFilter returns the monitoring filter of the metric source, from spec.filter or spec.query
+func cloudMonitoringFilter(metricSource MetricSource) (string, error) {
+	filter := strings.TrimSpace(metricSource.Spec.Scalar("filter"))
+	if filter == "" {
+		filter = strings.TrimSpace(metricSource.Spec.Scalar("query"))
+	}
+	if filter == "" {
+		return "", fmt.Errorf("metricSource.spec.filter is required")
+	}
+	return filter, nil
+}
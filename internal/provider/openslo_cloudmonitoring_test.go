@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of testdata")
+
+const cloudMonitoringYamlSpec = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: gcm
+spec:
+  type: google-cloud-monitoring
+  connectionDetails:
+    project: my-project
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: requests
+spec:
+  ratioMetric:
+    counter: true
+    good:
+      metricSource:
+        metricSourceRef: gcm
+        spec:
+          filter: metric.type="loadbalancing.googleapis.com/https/request_count" resource.type="https_lb_rule" metric.label.response_code_class="200"
+    total:
+      metricSource:
+        metricSourceRef: gcm
+        spec:
+          filter: metric.type="loadbalancing.googleapis.com/https/request_count" resource.type="https_lb_rule"
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        type: cloudmonitoring
+        spec:
+          filter: metric.type="run.googleapis.com/request_count" metric.label.response_code_class="5xx"
+    total:
+      metricSource:
+        type: cloudmonitoring
+        spec:
+          filter: metric.type="run.googleapis.com/request_count"
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: latency
+spec:
+  thresholdMetric:
+    metricSource:
+      type: stackdriver
+      spec:
+        filter: metric.type="run.googleapis.com/request_latencies"
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: frontend
+  displayName: Frontend availability
+  labels:
+    team: web
+spec:
+  indicatorRef: requests
+  timeWindow:
+  - duration: 28d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.995
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: api
+spec:
+  indicatorRef: errors
+  timeWindow:
+  - duration: 1M
+    isRolling: false
+    calendar:
+      startTime: "2023-01-01 00:00:00"
+      timeZone: UTC
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Available
+    target: 0.999
+  - displayName: Fast
+    targetPercent: 95
+    op: lte
+    value: 300
+    indicatorRef: latency
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: quarterly
+spec:
+  indicatorRef: errors
+  timeWindow:
+  - duration: 90d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+`
+
+func TestOpenSLOCloudMonitoringSlos_shouldMatch_goldenFiles(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(cloudMonitoringYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Cloud_monitoring_slos), []string{"api/Available", "api/Fast", "frontend/0"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	for key, sloJson := range openslo.Cloud_monitoring_slos {
+		golden := filepath.Join("testdata", "cloud_monitoring", strings.ToLower(strings.ReplaceAll(key, "/", "-"))+".json")
+		if *updateGolden {
+			if err := os.WriteFile(golden, []byte(sloJson+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sloJson+"\n" != string(expected) {
+			t.Errorf("Objective %s does not match %s, run the tests with -update to regenerate it\n%s", key, golden, sloJson)
+		}
+	}
+}
+
+func TestOpenSLOCloudMonitoringSlos_shouldbeWarning_unsupportedRollingPeriod(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(cloudMonitoringYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	warnings := warningsWithSummary(diagnostics, "Cannot render cloud monitoring SLO, skipping")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "quarterly/0: rolling timeWindow 90d") {
+		t.Errorf("Expected a warning naming the objective, but got %v", warnings)
+	}
+}
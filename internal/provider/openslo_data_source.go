@@ -22,16 +22,40 @@ func NewOpenSloDataSource() datasource.DataSource {
 
 // OpenSloDataSource defines the data source implementation.
 type OpenSloDataSource struct {
-	Yaml_input                 types.String                            `tfsdk:"yaml_input"`
-	Datasources                map[string]DataSourceModel              `tfsdk:"datasources"`
-	Services                   map[string]ServiceModel                 `tfsdk:"services"`
-	Alert_conditions           map[string]AlertConditionModel          `tfsdk:"alert_conditions"`
-	Alert_notification_targets map[string]AlertNotificationTargetModel `tfsdk:"alert_notification_targets"`
-	Alert_policies             map[string]AlertPolicyModel             `tfsdk:"alert_policies"`
-	Slis                       map[string]SLIModel                     `tfsdk:"slis"`
-	Slos                       map[string]SLOModel                     `tfsdk:"slos"`
-	Extension_browsermonitor   map[string]BrowserMonitorModel          `tfsdk:"extension_browsermonitor"`
-	Extension_httpmonitor      map[string]HTTPMonitorModel             `tfsdk:"extension_httpmonitor"`
+	Yaml_input                           types.String                            `tfsdk:"yaml_input"`
+	Selector                             *SelectorModel                          `tfsdk:"selector"`
+	Extension_blackbox_exporter_address  types.String                            `tfsdk:"extension_blackbox_exporter_address"`
+	Alertmanager_slack_api_url           types.String                            `tfsdk:"alertmanager_slack_api_url"`
+	Datasources                          map[string]DataSourceModel              `tfsdk:"datasources"`
+	Services                             map[string]ServiceModel                 `tfsdk:"services"`
+	Alert_conditions                     map[string]AlertConditionModel          `tfsdk:"alert_conditions"`
+	Alert_notification_targets           map[string]AlertNotificationTargetModel `tfsdk:"alert_notification_targets"`
+	Alert_policies                       map[string]AlertPolicyModel             `tfsdk:"alert_policies"`
+	Slis                                 map[string]SLIModel                     `tfsdk:"slis"`
+	Slos                                 map[string]SLOModel                     `tfsdk:"slos"`
+	Extension_browsermonitor             map[string]BrowserMonitorModel          `tfsdk:"extension_browsermonitor"`
+	Extension_httpmonitor                map[string]HTTPMonitorModel             `tfsdk:"extension_httpmonitor"`
+	Extension_tcpmonitor                 map[string]TCPMonitorModel              `tfsdk:"extension_tcpmonitor"`
+	Extension_dnsmonitor                 map[string]DNSMonitorModel              `tfsdk:"extension_dnsmonitor"`
+	Extension_grpcmonitor                map[string]GRPCMonitorModel             `tfsdk:"extension_grpcmonitor"`
+	Extension_tlsmonitor                 map[string]TLSMonitorModel              `tfsdk:"extension_tlsmonitor"`
+	Objectives                           map[string]FlatObjectiveModel           `tfsdk:"objectives"`
+	Burn_rate_alerts                     map[string]BurnRateAlertModel           `tfsdk:"burn_rate_alerts"`
+	Prometheus_rules                     map[string]string                       `tfsdk:"prometheus_rules"`
+	Prometheus_rule_manifests            map[string]string                       `tfsdk:"prometheus_rule_manifests"`
+	Alertmanager_config                  string                                  `tfsdk:"alertmanager_config"`
+	Datadog_slos                         map[string]DatadogSloModel              `tfsdk:"datadog_slos"`
+	Grafana_dashboards                   map[string]string                       `tfsdk:"grafana_dashboards"`
+	Pyrra_manifests                      map[string]string                       `tfsdk:"pyrra_manifests"`
+	Cloud_monitoring_slos                map[string]string                       `tfsdk:"cloud_monitoring_slos"`
+	Nobl9_manifests                      map[string]string                       `tfsdk:"nobl9_manifests"`
+	Extension_dynatrace_http_monitors    map[string]string                       `tfsdk:"extension_dynatrace_http_monitors"`
+	Extension_dynatrace_slos             map[string]string                       `tfsdk:"extension_dynatrace_slos"`
+	Extension_blackbox_exporter_config   string                                  `tfsdk:"extension_blackbox_exporter_config"`
+	Extension_blackbox_scrape_configs    string                                  `tfsdk:"extension_blackbox_scrape_configs"`
+	Extension_k6_scripts                 map[string]string                       `tfsdk:"extension_k6_scripts"`
+	Extension_playwright_scripts         map[string]string                       `tfsdk:"extension_playwright_scripts"`
+	Extension_dynatrace_browser_monitors map[string]string                       `tfsdk:"extension_dynatrace_browser_monitors"`
 }
 
 func (d *OpenSloDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -49,10 +73,22 @@ func (d *OpenSloDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				Optional:            false,
 				Required:            true,
 			},
-			"datasources": schema.MapAttribute{
-				MarkdownDescription: "Datasources",
+			"selector": SelectorSchema,
+			"extension_blackbox_exporter_address": schema.StringAttribute{
+				MarkdownDescription: "Address of the blackbox_exporter probing the HTTP monitors in `extension_blackbox_scrape_configs`. Defaults to " + BLACKBOX_DEFAULT_EXPORTER_ADDRESS,
+				Optional:            true,
+			},
+			"alertmanager_slack_api_url": schema.StringAttribute{
+				MarkdownDescription: "Slack incoming webhook url of the `alertmanager_config` slack channels, set as the global `slack_api_url`. Slack notification targets are skipped when it is not set",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"datasources": schema.MapNestedAttribute{
+				MarkdownDescription: "Datasources. `connection_details` is sensitive, values given as `env:VAR` or `file:/path` are resolved at read time, for the datasources kept by the `selector`. It is also sensitive in the datasources embedded in metric sources.",
 				Computed:            true,
-				ElementType:         DataSourceSchema,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ComputedAttributes(DataSourceSchema, "connection_details"),
+				},
 			},
 			"services": schema.MapAttribute{
 				MarkdownDescription: "Services",
@@ -74,26 +110,137 @@ func (d *OpenSloDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				Computed:            true,
 				ElementType:         AlertPolicySchema,
 			},
-			"slis": schema.MapAttribute{
-				MarkdownDescription: "SLIs",
+			"slis": schema.MapNestedAttribute{
+				MarkdownDescription: "SLIs. The `connection_details` of the embedded datasources are sensitive.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ComputedAttributes(SLISchema, SLIConnectionDetailsPaths...),
+				},
+			},
+			"slos": schema.MapNestedAttribute{
+				MarkdownDescription: "SLOs. The `connection_details` of the embedded datasources are sensitive.",
 				Computed:            true,
-				ElementType:         SLISchema,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ComputedAttributes(SLOSchema, append(prefixedPaths("indicator", SLIConnectionDetailsPaths...), prefixedPaths("objectives.indicator", SLIConnectionDetailsPaths...)...)...),
+				},
 			},
-			"slos": schema.MapAttribute{
-				MarkdownDescription: "SLOs",
+			"objectives": schema.MapNestedAttribute{
+				MarkdownDescription: "Every SLO objective, keyed by `slo/displayName` (or `slo/index` when the objective has no unique display name, or its display name is the index of an objective), with its resolved service, indicator, time window, budgeting method and alert policies. The `connection_details` of the embedded datasources are sensitive.",
 				Computed:            true,
-				ElementType:         SLOSchema,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ComputedAttributes(FlatObjectiveSchema, append(prefixedPaths("indicator", SLIConnectionDetailsPaths...), prefixedPaths("objective.indicator", SLIConnectionDetailsPaths...)...)...),
+				},
 			},
-			"extension_httpmonitor": schema.MapAttribute{
-				MarkdownDescription: "Synthetics HTTP (extension)",
+			"burn_rate_alerts": schema.MapAttribute{
+				MarkdownDescription: "Multi-window multi-burn-rate alerts derived from the `burnrate` alert conditions of every SLO objective, keyed by `objective/alertPolicy/alertCondition`. The long window is the condition `lookbackWindow`, the short window is 1/12 of it, and `expression` is a backend neutral form of the alert, e.g. `error_ratio(1h) > 0.0144 and error_ratio(5m) > 0.0144`.",
 				Computed:            true,
-				ElementType:         HTTPMonitorSchema,
+				ElementType:         BurnRateAlertSchema,
+			},
+			"prometheus_rules": schema.MapAttribute{
+				MarkdownDescription: "Prometheus rule files (yaml) keyed by SLO, with the SLI error ratio recording rules and the multi-window multi-burn-rate alerts of every prometheus backed SLO. Alerts use the `burnrate` alert conditions of the SLO, or the Google SRE workbook windows when it has none. Queries can use the &#123;&#123;.window&#125;&#125; placeholder, otherwise they must be series selectors. The SLO labels are set on every rule, invalid characters of their names are replaced by `_`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"prometheus_rule_manifests": schema.MapAttribute{
+				MarkdownDescription: "prometheus-operator `monitoring.coreos.com/v1` PrometheusRule manifests (yaml) keyed by service, with the `prometheus_rules` groups of the SLOs of the service. The name, namespace, labels and annotations are derived from the service metadata.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"alertmanager_config": schema.StringAttribute{
+				MarkdownDescription: "Alertmanager configuration (yaml) routing the `prometheus_rules` alerts of every SLO to its alert policies. Routes match the `openslo_service`, `openslo_slo` and `openslo_alert_policy` labels and the severities of the policy conditions. Referenced alert policies have one receiver named after the policy, inline ones a receiver per SLO named `slo/index`, which is also their `openslo_alert_policy` label. Notification targets can be an http(s) url, an email address, a `#slack-channel` (with `alertmanager_slack_api_url`) or a `pagerduty:<routing key>`. `alert_when_resolved` sets `send_resolved`, and `alert_when_no_data` routes the `OpenSLONoData` alert, which inhibits the burn rate alerts of the objective. It is sensitive, as it holds the slack webhook url and the pagerduty routing keys.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"datadog_slos": schema.MapAttribute{
+				MarkdownDescription: "Metric based Datadog SLOs keyed by objective, for every datadog backed SLO objective, ready to use in a `datadog_service_level_objective` resource. Targets are in percent, timeframes are one of `7d`, `30d` or `90d`, and tags are the SLO labels as `key:value`.",
+				Computed:            true,
+				ElementType:         DatadogSloSchema,
+			},
+			"grafana_dashboards": schema.MapAttribute{
+				MarkdownDescription: "Grafana dashboards (json) keyed by service, with the SLI, remaining error budget and burn rate of each SLO objective of the service. Prometheus backed objectives are charted from the `prometheus_rules` recording rules, through a `datasource` dashboard variable, other objectives get a text panel. The dashboard uid is `openslo-<service>`, ending with a hash of it when it had to be sanitized or truncated.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"pyrra_manifests": schema.MapAttribute{
+				MarkdownDescription: "Pyrra `ServiceLevelObjective` manifests (yaml) keyed by objective, for every prometheus backed SLO objective. Bad and total metrics give a ratio indicator, good `_bucket` and total metrics give a latency indicator, `syntheticMonitorRef` SLIs a `bool_gauge` indicator on the monitor probes, grouping labels are read from the total metric source `spec.grouping`. Queries must be series selectors. Manifests are named after the objective key, ending with a hash of it when it had to be lowercased, sanitized or truncated to a Kubernetes name.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"cloud_monitoring_slos": schema.MapAttribute{
+				MarkdownDescription: "Google Cloud Monitoring `projects.services.serviceLevelObjectives` (json) keyed by objective, for every cloud monitoring backed SLO objective (`cloudmonitoring`, `google-cloud-monitoring`, `stackdriver` or `gcm` datasource type). Ratio SLIs become a `goodTotalRatio`, threshold SLIs a `distributionCut`, filters are read from the metric source `spec.filter`.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"extension_httpmonitor": schema.MapNestedAttribute{
+				MarkdownDescription: "Synthetics HTTP (extension). The request `auth` is sensitive, credentials given as `env:VAR` or `file:/path` are kept as references, only `openslo_synthetics_check` resolves them. The rendered probes never contain the secrets: k6 scripts read them from `__ENV` or files, blackbox modules from files, and Dynatrace monitors need a credential vault entry.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ComputedAttributes(HTTPMonitorSchema, "requests.auth"),
+				},
 			},
 			"extension_browsermonitor": schema.MapAttribute{
 				MarkdownDescription: "Synthetics Browser (extension)",
 				Computed:            true,
 				ElementType:         BrowserMonitorSchema,
 			},
+			"extension_tcpmonitor": schema.MapAttribute{
+				MarkdownDescription: "Synthetics TCP port checks (extension)",
+				Computed:            true,
+				ElementType:         TCPMonitorSchema,
+			},
+			"extension_dnsmonitor": schema.MapAttribute{
+				MarkdownDescription: "Synthetics DNS resolution checks (extension), the record type defaults to A",
+				Computed:            true,
+				ElementType:         DNSMonitorSchema,
+			},
+			"extension_grpcmonitor": schema.MapAttribute{
+				MarkdownDescription: "Synthetics gRPC health checks (extension)",
+				Computed:            true,
+				ElementType:         GRPCMonitorSchema,
+			},
+			"extension_tlsmonitor": schema.MapAttribute{
+				MarkdownDescription: "Synthetics TLS certificate monitors (extension), the port defaults to 443 and the server name to the host",
+				Computed:            true,
+				ElementType:         TLSMonitorSchema,
+			},
+			"nobl9_manifests": schema.MapAttribute{
+				MarkdownDescription: "Nobl9 n9/v1alpha manifests (yaml) keyed by project/kind/name: the Project, Service, AlertPolicy and SLO objects of the SLOs with a prometheus, datadog or cloudwatch datasource. Projects are the SLO namespaces, services and alert policies are rendered in every project of their SLOs",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"extension_dynatrace_http_monitors": schema.MapAttribute{
+				MarkdownDescription: "Dynatrace synthetic HTTP monitors (json) keyed by HTTP monitor, with the requests, headers, validation rules and post-processing script of the monitor (extension)",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"extension_dynatrace_slos": schema.MapAttribute{
+				MarkdownDescription: "Dynatrace SLOs (json) keyed by objective, on the synthetic availability of the HTTP monitors of the SLO service (extension)",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"extension_blackbox_exporter_config": schema.StringAttribute{
+				MarkdownDescription: "blackbox_exporter configuration (yaml) with an http module per HTTP monitor request: method, headers, body, valid status codes, body and header regexps, and the maximum latency as timeout (extension)",
+				Computed:            true,
+			},
+			"extension_blackbox_scrape_configs": schema.StringAttribute{
+				MarkdownDescription: "Prometheus `scrape_configs` (yaml) probing every HTTP monitor request through the blackbox_exporter with its module (extension)",
+				Computed:            true,
+			},
+			"extension_k6_scripts": schema.MapAttribute{
+				MarkdownDescription: "k6 scripts (javascript) keyed by enabled HTTP monitor, issuing the requests in order with the monitor timeout and retries, and checking their expected response with `check()`. A script runs the monitor once, it must be scheduled at the monitor `frequency` (extension)",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"extension_playwright_scripts": schema.MapAttribute{
+				MarkdownDescription: "Playwright tests (javascript) keyed by enabled browser monitor, running the monitor `steps` (extension)",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"extension_dynatrace_browser_monitors": schema.MapAttribute{
+				MarkdownDescription: "Dynatrace clickpath browser monitors (json) keyed by browser monitor, running the monitor `steps` (extension)",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
 		},
 	}
 }
@@ -114,6 +261,9 @@ func (d *OpenSloDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
+	d.Selector = readData.Selector
+	d.Extension_blackbox_exporter_address = readData.Extension_blackbox_exporter_address
+	d.Alertmanager_slack_api_url = readData.Alertmanager_slack_api_url
 	err := d.GetOpenSloData(readData.Yaml_input.ValueString(), &resp.Diagnostics)
 	if err != nil {
 		return
@@ -135,6 +285,10 @@ func (d *OpenSloDataSource) GetOpenSloData(yamlInput string, diagnostics *diag.D
 	d.Alert_policies = map[string]AlertPolicyModel{}
 	d.Extension_browsermonitor = map[string]BrowserMonitorModel{}
 	d.Extension_httpmonitor = map[string]HTTPMonitorModel{}
+	d.Extension_tcpmonitor = map[string]TCPMonitorModel{}
+	d.Extension_dnsmonitor = map[string]DNSMonitorModel{}
+	d.Extension_grpcmonitor = map[string]GRPCMonitorModel{}
+	d.Extension_tlsmonitor = map[string]TLSMonitorModel{}
 
 	// We decode the yaml with 2 decoder iterators, so we can get the kind then unmarshal the yaml value
 
@@ -171,7 +325,13 @@ func (d *OpenSloDataSource) GetOpenSloData(yamlInput string, diagnostics *diag.D
 		}
 	}
 
-	err := d.OpenSloPostExtractionLogic()
+	err := d.SyntheticsExtensionIndicatorLogic()
+	if err != nil {
+		diagnostics.AddError("Synthetics Extension Indicator Error", err.Error())
+		return err
+	}
+
+	err = d.OpenSloPostExtractionLogic()
 	if err != nil {
 		diagnostics.AddError("OpenSLO Post Extraction Error", err.Error())
 		return err
@@ -183,5 +343,86 @@ func (d *OpenSloDataSource) GetOpenSloData(yamlInput string, diagnostics *diag.D
 		return err
 	}
 
+	err = d.ApplySelector()
+	if err != nil {
+		diagnostics.AddError("Selector Error", err.Error())
+		return err
+	}
+
+	err = d.ResolveSecretReferences()
+	if err != nil {
+		diagnostics.AddError("OpenSLO Secret Reference Error", err.Error())
+		return err
+	}
+
+	d.FlattenObjectives()
+	d.ComputeBurnRateAlerts(diagnostics)
+
+	err = d.RenderPrometheusRules(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Prometheus Rules Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.RenderPrometheusRuleManifests()
+	if err != nil {
+		diagnostics.AddError("Prometheus Rule Manifests Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.RenderAlertmanagerConfig(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Alertmanager Config Rendering Error", err.Error())
+		return err
+	}
+
+	d.RenderDatadogSlos(diagnostics)
+
+	err = d.RenderGrafanaDashboards()
+	if err != nil {
+		diagnostics.AddError("Grafana Dashboards Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.RenderPyrraManifests(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Pyrra Manifests Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.RenderCloudMonitoringSlos(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Cloud Monitoring SLOs Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.RenderNobl9Manifests(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Nobl9 Manifests Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.SyntheticsExtensionRenderDynatrace(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Synthetics Extension Dynatrace Rendering Error", err.Error())
+		return err
+	}
+
+	err = d.SyntheticsExtensionRenderBlackbox(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Synthetics Extension Blackbox Rendering Error", err.Error())
+		return err
+	}
+
+	d.SyntheticsExtensionIndicatorWarnings(diagnostics)
+
+	d.SyntheticsExtensionRenderK6(diagnostics)
+
+	err = d.SyntheticsExtensionRenderBrowser(diagnostics)
+	if err != nil {
+		diagnostics.AddError("Synthetics Extension Browser Rendering Error", err.Error())
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const DATADOG_SLO_TYPE_METRIC = "metric"
+
+// Datadog SLOs only support these rolling timeframes
+var datadogTimeframes = []string{"7d", "30d", "90d"}
+
+// RenderDatadogSlos renders the Datadog SLO of every datadog backed objective, keyed by objective
+func (d *OpenSloDataSource) RenderDatadogSlos(diagnostics *diag.Diagnostics) {
+	d.Datadog_slos = map[string]DatadogSloModel{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			objective := slo.Objectives[i]
+			indicator := ObjectiveIndicator(slo, objective)
+			if indicatorMetricSourceType(indicator) != "datadog" {
+				continue
+			}
+
+			datadogSlo, err := DatadogSlo(sloName, key, slo, objective, indicator)
+			if err != nil {
+				diagnostics.AddWarning("Cannot render datadog SLO, skipping", fmt.Sprintf("objective %s: %s", key, err.Error()))
+				continue
+			}
+			d.Datadog_slos[key] = *datadogSlo
+		}
+	}
+}
+
+// DatadogSlo returns the metric based Datadog SLO of an objective, with one threshold per time window of the SLO
+func DatadogSlo(sloName string, key string, slo SLOModel, objective ObjectiveModel, indicator SLIModel) (*DatadogSloModel, error) {
+	query, err := DatadogSloQuery(indicator)
+	if err != nil {
+		return nil, err
+	}
+
+	target := ObjectiveTarget(objective)
+	if target <= 0 || target >= 1 {
+		return nil, fmt.Errorf("target must be between 0 and 1, got %v", target)
+	}
+	timeWindows := slo.TimeWindow
+	if len(timeWindows) == 0 {
+		timeWindows = []TimeWindowModel{{Duration: DEFAULT_SLO_TIME_WINDOW, IsRolling: true}}
+	}
+	var thresholds []DatadogSloThresholdModel
+	for _, timeWindow := range timeWindows {
+		timeframe, err := DatadogTimeframe(timeWindow)
+		if err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, DatadogSloThresholdModel{
+			Timeframe: timeframe,
+			Target:    roundFloat(target * 100),
+		})
+	}
+
+	name := slo.Metadata.DisplayName
+	if name == "" {
+		name = sloName
+	}
+	if len(slo.Objectives) > 1 {
+		name = fmt.Sprintf("%s - %s", name, strings.TrimPrefix(key, sloName+"/"))
+	}
+
+	return &DatadogSloModel{
+		Name:        name,
+		Type:        DATADOG_SLO_TYPE_METRIC,
+		Description: slo.Description,
+		Query:       *query,
+		Thresholds:  thresholds,
+		Tags:        DatadogTags(slo),
+	}, nil
+}
+
+// DatadogSloQuery returns the numerator (good events) and denominator (total events) queries of a ratio SLI
+func DatadogSloQuery(sli SLIModel) (*DatadogSloQueryModel, error) {
+	if isMetricSourceSet(sli.ThresholdMetric.MetricSource) {
+		return nil, fmt.Errorf("thresholdMetric is not supported by metric based Datadog SLOs")
+	}
+	ratio := sli.RatioMetric
+	if ratio.RawType != "" || isMetricSourceSet(ratio.Raw.MetricSource) {
+		return nil, fmt.Errorf("ratioMetric.raw is not supported by metric based Datadog SLOs")
+	}
+
+	queries := map[string]string{}
+	for _, metric := range []struct {
+		name         string
+		metricSource MetricSource
+	}{
+		{"good", ratio.Good.MetricSource},
+		{"bad", ratio.Bad.MetricSource},
+		{"total", ratio.Total.MetricSource},
+	} {
+		if !isMetricSourceSet(metric.metricSource) {
+			continue
+		}
+		query := strings.TrimSpace(metric.metricSource.Spec.Scalar("query"))
+		if query == "" {
+			return nil, fmt.Errorf("ratioMetric.%s.metricSource.spec.query is required", metric.name)
+		}
+		queries[metric.name] = query
+	}
+
+	good, bad, total := queries["good"], queries["bad"], queries["total"]
+	switch {
+	case good != "" && total != "":
+		return &DatadogSloQueryModel{Numerator: good, Denominator: total}, nil
+	case bad != "" && total != "":
+		return &DatadogSloQueryModel{Numerator: fmt.Sprintf("%s - %s", total, bad), Denominator: total}, nil
+	case good != "" && bad != "":
+		return &DatadogSloQueryModel{Numerator: good, Denominator: fmt.Sprintf("%s + %s", good, bad)}, nil
+	}
+	return nil, fmt.Errorf("ratioMetric needs a total, or both good and bad metrics")
+}
+
+// DatadogTimeframe returns the Datadog timeframe of a rolling time window
+func DatadogTimeframe(timeWindow TimeWindowModel) (string, error) {
+	if !timeWindow.IsRolling {
+		return "", fmt.Errorf("calendar time windows are not supported by Datadog SLOs")
+	}
+	duration, err := ParseOpenSloDuration(timeWindow.Duration)
+	if err != nil {
+		return "", fmt.Errorf("timeWindow: %w", err)
+	}
+	timeframe := FormatPrometheusDuration(duration)
+	if !containsString(datadogTimeframes, timeframe) {
+		return "", fmt.Errorf("timeWindow %s is not supported by Datadog SLOs, expected one of %s", timeWindow.Duration, strings.Join(datadogTimeframes, ", "))
+	}
+	return timeframe, nil
+}
+
+// DatadogTags returns the SLO labels as sorted key:value tags, with a service tag when the SLO references one
+func DatadogTags(slo SLOModel) []string {
+	tags := []string{}
+	for key, value := range slo.Metadata.Labels {
+		tags = append(tags, fmt.Sprintf("%s:%s", key, value))
+	}
+	if _, ok := slo.Metadata.Labels["service"]; !ok && slo.ServiceRef != "" {
+		tags = append(tags, fmt.Sprintf("service:%s", slo.ServiceRef))
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const datadogYamlSpec = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: datadog
+spec:
+  type: datadog
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+spec:
+  description: Checkout service
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: requests
+spec:
+  ratioMetric:
+    counter: true
+    good:
+      metricSource:
+        metricSourceRef: datadog
+        spec:
+          query: sum:requests.ok{service:checkout}.as_count()
+    total:
+      metricSource:
+        metricSourceRef: datadog
+        spec:
+          query: sum:requests{service:checkout}.as_count()
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        metricSourceRef: datadog
+        spec:
+          query: sum:requests.error{*}.as_count()
+    total:
+      metricSource:
+        metricSourceRef: datadog
+        spec:
+          query: sum:requests{*}.as_count()
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+  displayName: Checkout availability
+  labels:
+    team: payments
+spec:
+  description: Checkout requests succeed
+  service: checkout
+  indicatorRef: requests
+  timeWindow:
+  - duration: 7d
+    isRolling: true
+  - duration: 1M
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: errors
+spec:
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Strict
+    targetPercent: 99.5
+  - displayName: Lenient
+    target: 0.99
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: monthly
+spec:
+  indicatorRef: errors
+  timeWindow:
+  - duration: 28d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+`
+
+func TestOpenSLODatadogSlos_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(datadogYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Datadog_slos), []string{"checkout-availability/0", "errors/Lenient", "errors/Strict"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	expected := DatadogSloModel{
+		Name:        "Checkout availability",
+		Type:        "metric",
+		Description: "Checkout requests succeed",
+		Query: DatadogSloQueryModel{
+			Numerator:   "sum:requests.ok{service:checkout}.as_count()",
+			Denominator: "sum:requests{service:checkout}.as_count()",
+		},
+		Thresholds: []DatadogSloThresholdModel{
+			{Timeframe: "7d", Target: 99.9},
+			{Timeframe: "30d", Target: 99.9},
+		},
+		Tags: []string{"service:checkout", "team:payments"},
+	}
+	diff = deep.Equal(openslo.Datadog_slos["checkout-availability/0"], expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and bad events are subtracted from the total
+	expected = DatadogSloModel{
+		Name: "errors - Strict",
+		Type: "metric",
+		Query: DatadogSloQueryModel{
+			Numerator:   "sum:requests{*}.as_count() - sum:requests.error{*}.as_count()",
+			Denominator: "sum:requests{*}.as_count()",
+		},
+		Thresholds: []DatadogSloThresholdModel{
+			{Timeframe: "30d", Target: 99.5},
+		},
+		Tags: []string{},
+	}
+	diff = deep.Equal(openslo.Datadog_slos["errors/Strict"], expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	openSloState(t, &openslo)
+}
+
+func TestOpenSLODatadogSlos_shouldbeWarning_unsupportedTimeWindow(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(datadogYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	if _, ok := openslo.Datadog_slos["monthly/0"]; ok {
+		t.Error("Expected 28d SLO to be skipped")
+	}
+	warnings := warningsWithSummary(diagnostics, "Cannot render datadog SLO, skipping")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "monthly/0: timeWindow 28d") {
+		t.Errorf("Expected a warning naming the objective, but got %v", warnings)
+	}
+}
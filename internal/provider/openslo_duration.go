@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const DAY = 24 * time.Hour
+
+var openSloDurationRegex = regexp.MustCompile(`^([0-9]+)(s|m|h|d|w|M|Q|Y)$`)
+
+// Calendar units have no fixed length, they are approximated
+var openSloDurationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": DAY,
+	"w": 7 * DAY,
+	"M": 30 * DAY,
+	"Q": 90 * DAY,
+	"Y": 365 * DAY,
+}
+
+// ParseOpenSloDuration parses the OpenSLO duration shorthand (e.g. 5m, 1h, 28d, 1M)
+func ParseOpenSloDuration(duration string) (time.Duration, error) {
+	match := openSloDurationRegex.FindStringSubmatch(duration)
+	if match == nil {
+		return 0, fmt.Errorf("bad duration: %q, expected a number followed by one of s, m, h, d, w, M, Q or Y", duration)
+	}
+	count, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad duration: %q: %w", duration, err)
+	}
+	return time.Duration(count) * openSloDurationUnits[match[2]], nil
+}
+
+// FormatPrometheusDuration formats a duration with the largest prometheus unit that fits (e.g. 30d, 90m)
+func FormatPrometheusDuration(duration time.Duration) string {
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{
+		{"d", DAY},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	} {
+		if duration >= unit.length && duration%unit.length == 0 {
+			return fmt.Sprintf("%d%s", duration/unit.length, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dms", duration.Milliseconds())
+}
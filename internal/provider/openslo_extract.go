@@ -3,6 +3,7 @@ package provider
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/goccy/go-yaml"
 )
@@ -98,6 +99,9 @@ func (d *OpenSloDataSource) ExtractOpenSloDocument(doc *YamlSpec, decType *yaml.
 }
 
 func (d *OpenSloDataSource) OpenSloPostExtractionLogic() error {
+	// Compute back references before embedding, so the embedded copies have them too
+	d.computeBackReferences()
+
 	// Embed referenced objects for alert policies
 	for i := range d.Alert_policies {
 		for j := range d.Alert_policies[i].Conditions {
@@ -229,3 +233,59 @@ func (d *OpenSloDataSource) OpenSloPostExtractionLogic() error {
 
 	return nil
 }
+
+// computeBackReferences fills the lists of objects referencing each service, sli and datasource
+func (d *OpenSloDataSource) computeBackReferences() {
+	sloNamesByService := map[string][]string{}
+	sloNamesBySli := map[string][]string{}
+	for name, slo := range d.Slos {
+		if slo.ServiceRef != "" {
+			sloNamesByService[slo.ServiceRef] = appendUnique(sloNamesByService[slo.ServiceRef], name)
+		}
+		if slo.IndicatorRef != "" {
+			sloNamesBySli[slo.IndicatorRef] = appendUnique(sloNamesBySli[slo.IndicatorRef], name)
+		}
+		for _, objective := range slo.Objectives {
+			if objective.IndicatorRef != "" {
+				sloNamesBySli[objective.IndicatorRef] = appendUnique(sloNamesBySli[objective.IndicatorRef], name)
+			}
+		}
+	}
+
+	sliNamesByDatasource := map[string][]string{}
+	for name, sli := range d.Slis {
+		for _, metricSource := range sli.metricSources() {
+			if metricSource.MetricSourceRef != "" {
+				sliNamesByDatasource[metricSource.MetricSourceRef] = appendUnique(sliNamesByDatasource[metricSource.MetricSourceRef], name)
+			}
+		}
+	}
+
+	for k := range d.Services {
+		service := d.Services[k]
+		service.Slos = sortedStrings(sloNamesByService[k])
+		d.Services[k] = service
+	}
+	for k := range d.Slis {
+		sli := d.Slis[k]
+		sli.UsedBySlos = sortedStrings(sloNamesBySli[k])
+		d.Slis[k] = sli
+	}
+	for k := range d.Datasources {
+		datasource := d.Datasources[k]
+		datasource.UsedBySlis = sortedStrings(sliNamesByDatasource[k])
+		d.Datasources[k] = datasource
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func sortedStrings(values []string) []string {
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// FreeformMap holds the free-form yaml objects of the spec (metricSource.spec, connectionDetails)
+// with their full structure. It is exposed to terraform as a map of strings: scalar values are kept
+// as is, and nested objects or lists are JSON-encoded so they can be read back with jsondecode().
+type FreeformMap map[string]interface{}
+
+var _ tftypes.ValueCreator = FreeformMap{}
+
+func (f FreeformMap) ToTerraform5Value() (interface{}, error) {
+	if f == nil {
+		return nil, nil
+	}
+	values := map[string]tftypes.Value{}
+	for key, value := range f {
+		if value == nil {
+			values[key] = tftypes.NewValue(tftypes.String, nil)
+			continue
+		}
+		encoded, err := encodeFreeformValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode key %s: %w", key, err)
+		}
+		values[key] = tftypes.NewValue(tftypes.String, encoded)
+	}
+	return values, nil
+}
+
+func encodeFreeformValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	}
+	encoded, err := json.Marshal(normalizeFreeformValue(value))
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// The yaml decoder may produce maps with non string keys, which encoding/json refuses
+func normalizeFreeformValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := map[string]interface{}{}
+		for key, item := range v {
+			normalized[key] = normalizeFreeformValue(item)
+		}
+		return normalized
+	case FreeformMap:
+		return normalizeFreeformValue(map[string]interface{}(v))
+	case map[interface{}]interface{}:
+		normalized := map[string]interface{}{}
+		for key, item := range v {
+			normalized[fmt.Sprint(key)] = normalizeFreeformValue(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeFreeformValue(item)
+		}
+		return normalized
+	}
+	return value
+}
+
+// Scalar returns the value of key as a string, or an empty string if it is missing or nested
+func (f FreeformMap) Scalar(key string) string {
+	switch value := f[key].(type) {
+	case nil, map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		return ""
+	default:
+		encoded, err := encodeFreeformValue(value)
+		if err != nil {
+			return ""
+		}
+		return encoded
+	}
+}
+
+// StringList returns the scalar items of the list at key, a scalar value is read as a comma separated list
+func (f FreeformMap) StringList(key string) []string {
+	var items []string
+	switch value := f[key].(type) {
+	case []interface{}:
+		for _, item := range value {
+			if encoded, err := encodeFreeformValue(item); err == nil && encoded != "" {
+				items = append(items, encoded)
+			}
+		}
+	default:
+		for _, item := range strings.Split(f.Scalar(key), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+const (
+	GRAFANA_SCHEMA_VERSION      = 36
+	GRAFANA_UID_MAX_LENGTH      = 40
+	GRAFANA_DATASOURCE_VARIABLE = "datasource"
+	GRAFANA_DASHBOARD_TAG       = "openslo"
+	GRAFANA_GRID_WIDTH          = 24
+	NAME_HASH_LENGTH            = 8
+)
+
+var grafanaUidInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+type GrafanaDashboard struct {
+	Uid           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description,omitempty"`
+	Tags          []string          `json:"tags"`
+	Editable      bool              `json:"editable"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Time          GrafanaTimeRange  `json:"time"`
+	Templating    GrafanaTemplating `json:"templating"`
+	Panels        []GrafanaPanel    `json:"panels"`
+}
+
+type GrafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type GrafanaTemplating struct {
+	List []GrafanaVariable `json:"list"`
+}
+
+type GrafanaVariable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type GrafanaPanel struct {
+	Id          int                    `json:"id"`
+	Type        string                 `json:"type"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	GridPos     GrafanaGridPos         `json:"gridPos"`
+	Datasource  *GrafanaDatasourceRef  `json:"datasource,omitempty"`
+	Targets     []GrafanaTarget        `json:"targets,omitempty"`
+	FieldConfig *GrafanaFieldConfig    `json:"fieldConfig,omitempty"`
+	Options     map[string]interface{} `json:"options,omitempty"`
+}
+
+type GrafanaGridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type GrafanaDatasourceRef struct {
+	Type string `json:"type"`
+	Uid  string `json:"uid"`
+}
+
+type GrafanaTarget struct {
+	RefId        string                `json:"refId"`
+	Expr         string                `json:"expr"`
+	LegendFormat string                `json:"legendFormat,omitempty"`
+	Datasource   *GrafanaDatasourceRef `json:"datasource,omitempty"`
+}
+
+type GrafanaFieldConfig struct {
+	Defaults  GrafanaFieldDefaults `json:"defaults"`
+	Overrides []interface{}        `json:"overrides"`
+}
+
+type GrafanaFieldDefaults struct {
+	Unit       string                 `json:"unit,omitempty"`
+	Decimals   int                    `json:"decimals,omitempty"`
+	Thresholds *GrafanaThresholds     `json:"thresholds,omitempty"`
+	Custom     map[string]interface{} `json:"custom,omitempty"`
+}
+
+type GrafanaThresholds struct {
+	Mode  string                 `json:"mode"`
+	Steps []GrafanaThresholdStep `json:"steps"`
+}
+
+// GrafanaThresholdStep is a threshold color, the base step has a nil value
+type GrafanaThresholdStep struct {
+	Color string   `json:"color"`
+	Value *float64 `json:"value"`
+}
+
+// RenderGrafanaDashboards renders one dashboard (json) per service, with the SLI, remaining error budget and burn rate of each of its SLOs
+func (d *OpenSloDataSource) RenderGrafanaDashboards() error {
+	d.Grafana_dashboards = map[string]string{}
+	dashboards := map[string]*GrafanaDashboard{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		if slo.ServiceRef == "" {
+			continue
+		}
+		dashboard, ok := dashboards[slo.ServiceRef]
+		if !ok {
+			dashboard = NewGrafanaDashboard(slo.ServiceRef, slo.Service)
+			dashboards[slo.ServiceRef] = dashboard
+		}
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			dashboard.AddObjective(sloName, slo, key, slo.Objectives[i])
+		}
+	}
+
+	for serviceName, dashboard := range dashboards {
+		dashboardJson, err := json.MarshalIndent(dashboard, "", "  ")
+		if err != nil {
+			return fmt.Errorf("service %s: %w", serviceName, err)
+		}
+		d.Grafana_dashboards[serviceName] = string(dashboardJson)
+	}
+	return nil
+}
+
+// NewGrafanaDashboard returns an empty dashboard for the service, with a prometheus datasource variable
+func NewGrafanaDashboard(serviceName string, service ServiceModel) *GrafanaDashboard {
+	title := service.Metadata.DisplayName
+	if title == "" {
+		title = serviceName
+	}
+	return &GrafanaDashboard{
+		Uid:           GrafanaUid("openslo-" + serviceName),
+		Title:         fmt.Sprintf("%s SLOs", title),
+		Description:   service.Description,
+		Tags:          []string{GRAFANA_DASHBOARD_TAG},
+		Editable:      true,
+		SchemaVersion: GRAFANA_SCHEMA_VERSION,
+		Time:          GrafanaTimeRange{From: "now-7d", To: "now"},
+		Templating: GrafanaTemplating{
+			List: []GrafanaVariable{
+				{Name: GRAFANA_DATASOURCE_VARIABLE, Label: "Data source", Type: "datasource", Query: "prometheus"},
+			},
+		},
+		Panels: []GrafanaPanel{},
+	}
+}
+
+// AddObjective adds a row of panels for the objective. Prometheus backed objectives are charted from the
+// recording rules of prometheus_rules, any other objective gets a text panel describing it.
+func (g *GrafanaDashboard) AddObjective(sloName string, slo SLOModel, key string, objective ObjectiveModel) {
+	y := g.nextY()
+	g.addPanel(GrafanaPanel{
+		Type:    "row",
+		Title:   key,
+		GridPos: GrafanaGridPos{X: 0, Y: y, W: GRAFANA_GRID_WIDTH, H: 1},
+	})
+	y++
+
+	indicator := ObjectiveIndicator(slo, objective)
+	if !IsPrometheusIndicator(indicator) {
+		g.addTextPanel(key, y, fmt.Sprintf("This objective uses a `%s` metric source, only prometheus SLIs are charted.", metricSourceTypeOrUnknown(indicator)), slo, objective)
+		return
+	}
+	if _, err := prometheusObjectiveRules(sloName, slo, key, objective, indicator); err != nil {
+		g.addTextPanel(key, y, fmt.Sprintf("No prometheus rules can be rendered for this objective: %s", err.Error()), slo, objective)
+		return
+	}
+
+	datasource := &GrafanaDatasourceRef{Type: "prometheus", Uid: "${" + GRAFANA_DATASOURCE_VARIABLE + "}"}
+	selector := PrometheusObjectiveSelector(sloName, key)
+	g.addPanel(GrafanaPanel{
+		Type:       "timeseries",
+		Title:      "SLI",
+		GridPos:    GrafanaGridPos{X: 0, Y: y, W: 10, H: 8},
+		Datasource: datasource,
+		Targets: []GrafanaTarget{
+			{RefId: "A", Expr: fmt.Sprintf("1 - %s%s", PrometheusErrorRatioRecord("5m"), selector), LegendFormat: "SLI", Datasource: datasource},
+			{RefId: "B", Expr: fmt.Sprintf("slo:objective:ratio%s", selector), LegendFormat: "Objective", Datasource: datasource},
+		},
+		FieldConfig: &GrafanaFieldConfig{
+			Defaults:  GrafanaFieldDefaults{Unit: "percentunit"},
+			Overrides: []interface{}{},
+		},
+	})
+	g.addPanel(GrafanaPanel{
+		Type:       "stat",
+		Title:      "Remaining error budget",
+		GridPos:    GrafanaGridPos{X: 10, Y: y, W: 4, H: 8},
+		Datasource: datasource,
+		Targets: []GrafanaTarget{
+			{RefId: "A", Expr: fmt.Sprintf("slo:period_error_budget_remaining:ratio%s", selector), LegendFormat: "Remaining", Datasource: datasource},
+		},
+		FieldConfig: &GrafanaFieldConfig{
+			Defaults: GrafanaFieldDefaults{
+				Unit:       "percentunit",
+				Decimals:   2,
+				Thresholds: grafanaThresholds("red", grafanaThresholdStep("orange", 0), grafanaThresholdStep("green", 0.25)),
+			},
+			Overrides: []interface{}{},
+		},
+		Options: map[string]interface{}{
+			"colorMode": "background",
+			"graphMode": "none",
+			"reduceOptions": map[string]interface{}{
+				"calcs": []string{"lastNotNull"},
+			},
+		},
+	})
+	g.addPanel(GrafanaPanel{
+		Type:       "timeseries",
+		Title:      "Burn rate",
+		GridPos:    GrafanaGridPos{X: 14, Y: y, W: 10, H: 8},
+		Datasource: datasource,
+		Targets: []GrafanaTarget{
+			{RefId: "A", Expr: fmt.Sprintf("slo:current_burn_rate:ratio%s", selector), LegendFormat: "Burn rate", Datasource: datasource},
+		},
+		FieldConfig: &GrafanaFieldConfig{
+			Defaults: GrafanaFieldDefaults{
+				Thresholds: grafanaThresholds("green", grafanaThresholdStep("red", 1)),
+				Custom: map[string]interface{}{
+					"thresholdsStyle": map[string]interface{}{"mode": "line"},
+				},
+			},
+			Overrides: []interface{}{},
+		},
+	})
+}
+
+func (g *GrafanaDashboard) addTextPanel(key string, y int, content string, slo SLOModel, objective ObjectiveModel) {
+	g.addPanel(GrafanaPanel{
+		Type:    "text",
+		Title:   key,
+		GridPos: GrafanaGridPos{X: 0, Y: y, W: GRAFANA_GRID_WIDTH, H: 4},
+		Options: map[string]interface{}{
+			"mode": "markdown",
+			"content": fmt.Sprintf("%s\n\nObjective: %s%% over %s (%s)",
+				content, formatPrometheusFloat(ObjectiveTarget(objective)*100), SloTimeWindow(slo), slo.BudgetingMethod),
+		},
+	})
+}
+
+func (g *GrafanaDashboard) addPanel(panel GrafanaPanel) {
+	panel.Id = len(g.Panels) + 1
+	g.Panels = append(g.Panels, panel)
+}
+
+// nextY returns the row below the lowest panel
+func (g *GrafanaDashboard) nextY() int {
+	y := 0
+	for _, panel := range g.Panels {
+		if bottom := panel.GridPos.Y + panel.GridPos.H; bottom > y {
+			y = bottom
+		}
+	}
+	return y
+}
+
+// grafanaThresholds returns absolute thresholds, the base color applies below the first step
+func grafanaThresholds(baseColor string, steps ...GrafanaThresholdStep) *GrafanaThresholds {
+	return &GrafanaThresholds{
+		Mode:  "absolute",
+		Steps: append([]GrafanaThresholdStep{{Color: baseColor}}, steps...),
+	}
+}
+
+func grafanaThresholdStep(color string, value float64) GrafanaThresholdStep {
+	return GrafanaThresholdStep{Color: color, Value: &value}
+}
+
+func metricSourceTypeOrUnknown(sli SLIModel) string {
+	sourceType := indicatorMetricSourceType(sli)
+	if sourceType == "" {
+		return "unknown"
+	}
+	return sourceType
+}
+
+// GrafanaUid converts a key to a valid dashboard uid. A uid that had to be changed or truncated ends with a hash of
+// the key, so that different keys do not overwrite each other's dashboard.
+func GrafanaUid(key string) string {
+	uid := grafanaUidInvalidChars.ReplaceAllString(key, "-")
+	if uid == key && len(uid) <= GRAFANA_UID_MAX_LENGTH {
+		return uid
+	}
+	return truncateWithHash(uid, key, GRAFANA_UID_MAX_LENGTH)
+}
+
+// truncateWithHash truncates a name derived from a key to fit the max length with a hash of the key appended
+func truncateWithHash(name string, key string, maxLength int) string {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])[:NAME_HASH_LENGTH]
+	if len(name) > maxLength-len(hash)-1 {
+		name = name[:maxLength-len(hash)-1]
+	}
+	return name + "-" + hash
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestOpenSLOGrafanaDashboards_shouldbeValid(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(prometheusYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and SLOs without a service have no dashboard
+	diff := deep.Equal(sortedKeys(openslo.Grafana_dashboards), []string{"checkout"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	var dashboard GrafanaDashboard
+	err = json.Unmarshal([]byte(openslo.Grafana_dashboards["checkout"]), &dashboard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.Uid != "openslo-checkout" || dashboard.Title != "checkout SLOs" || dashboard.Description != "Checkout service" {
+		t.Errorf("Unexpected dashboard %s %s %s", dashboard.Uid, dashboard.Title, dashboard.Description)
+	}
+
+	// and prometheus objectives are charted, others are described
+	var panels []string
+	for _, panel := range dashboard.Panels {
+		panels = append(panels, panel.Type+":"+panel.Title)
+	}
+	diff = deep.Equal(panels, []string{
+		"row:checkout-availability/Available",
+		"timeseries:SLI",
+		"stat:Remaining error budget",
+		"timeseries:Burn rate",
+		"row:checkout-latency/Fast",
+		"text:checkout-latency/Fast",
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	budget := dashboard.Panels[2]
+	expected := `slo:period_error_budget_remaining:ratio{openslo_slo="checkout-availability", openslo_objective="Available"}`
+	if budget.Targets[0].Expr != expected || budget.Datasource.Uid != "${datasource}" {
+		t.Errorf("Expected %s on the datasource variable, but got %+v", expected, budget.Targets[0])
+	}
+	if dashboard.Panels[4].GridPos.Y != 9 {
+		t.Errorf("Expected second row below the first one, but got %+v", dashboard.Panels[4].GridPos)
+	}
+	if !strings.Contains(dashboard.Panels[5].Options["content"].(string), "Objective: 95% over 30d (Timeslices)") {
+		t.Errorf("Expected text panel to describe the objective, but got %v", dashboard.Panels[5].Options["content"])
+	}
+}
+
+func TestOpenSLOGrafanaDashboards_shouldbeUnique_truncatedUids(t *testing.T) {
+	// when
+	first := NewGrafanaDashboard("checkout-payments-authorization-service-eu", ServiceModel{}).Uid
+	second := NewGrafanaDashboard("checkout-payments-authorization-service-us", ServiceModel{}).Uid
+	invalid := NewGrafanaDashboard("checkout.eu", ServiceModel{}).Uid
+
+	// then
+	if first == second || len(first) > GRAFANA_UID_MAX_LENGTH || len(second) > GRAFANA_UID_MAX_LENGTH {
+		t.Errorf("Expected different uids of at most %d characters, got %s and %s", GRAFANA_UID_MAX_LENGTH, first, second)
+	}
+	if !strings.HasPrefix(invalid, "openslo-checkout-eu-") || invalid == GrafanaUid("openslo-checkout-eu") {
+		t.Errorf("Expected a hashed uid for a renamed service, got %s", invalid)
+	}
+}
@@ -0,0 +1,541 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	NOBL9_API_VERSION         = "n9/v1alpha"
+	NOBL9_DEFAULT_PROJECT     = "default"
+	NOBL9_METRIC_SOURCE_AGENT = "Agent"
+	NOBL9_BURN_RATE           = "averageBurnRate"
+)
+
+// Metric source types with a nobl9 query mapping
+var nobl9SourceTypes = []string{"prometheus", "datadog", "cloudwatch"}
+
+// Nobl9 time window units, by OpenSLO duration unit
+var nobl9TimeUnits = map[string]string{
+	"m": "Minute",
+	"h": "Hour",
+	"d": "Day",
+	"w": "Week",
+	"M": "Month",
+	"Q": "Quarter",
+	"Y": "Year",
+}
+
+// Nobl9 alert policy severities, by OpenSLO severity, from the highest to the lowest
+var nobl9Severities = []struct {
+	severity string
+	aliases  []string
+}{
+	{"High", []string{"high", "critical", "page"}},
+	{"Medium", []string{"medium", "warning", "ticket"}},
+	{"Low", []string{"low", "info"}},
+}
+
+type Nobl9Manifest struct {
+	ApiVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	Metadata   Nobl9Metadata `yaml:"metadata"`
+	Spec       interface{}   `yaml:"spec"`
+}
+
+type Nobl9Metadata struct {
+	Name        string              `yaml:"name"`
+	DisplayName string              `yaml:"displayName,omitempty"`
+	Project     string              `yaml:"project,omitempty"`
+	Labels      map[string][]string `yaml:"labels,omitempty"`
+}
+
+type Nobl9DescriptionSpec struct {
+	Description string `yaml:"description,omitempty"`
+}
+
+type Nobl9SloSpec struct {
+	Description     string            `yaml:"description,omitempty"`
+	Service         string            `yaml:"service"`
+	Indicator       Nobl9Indicator    `yaml:"indicator"`
+	TimeWindows     []Nobl9TimeWindow `yaml:"timeWindows"`
+	BudgetingMethod string            `yaml:"budgetingMethod"`
+	Objectives      []Nobl9Objective  `yaml:"objectives"`
+	AlertPolicies   []string          `yaml:"alertPolicies,omitempty"`
+}
+
+type Nobl9Indicator struct {
+	MetricSource Nobl9MetricSourceRef `yaml:"metricSource"`
+}
+
+type Nobl9MetricSourceRef struct {
+	Name    string `yaml:"name"`
+	Project string `yaml:"project"`
+	Kind    string `yaml:"kind"`
+}
+
+type Nobl9TimeWindow struct {
+	Unit      string         `yaml:"unit"`
+	Count     int64          `yaml:"count"`
+	IsRolling bool           `yaml:"isRolling"`
+	Calendar  *Nobl9Calendar `yaml:"calendar,omitempty"`
+}
+
+type Nobl9Calendar struct {
+	StartTime string `yaml:"startTime"`
+	TimeZone  string `yaml:"timeZone"`
+}
+
+type Nobl9Objective struct {
+	Name            string             `yaml:"name"`
+	DisplayName     string             `yaml:"displayName,omitempty"`
+	Value           float64            `yaml:"value"`
+	Target          float64            `yaml:"target"`
+	Op              string             `yaml:"op,omitempty"`
+	TimeSliceTarget float64            `yaml:"timeSliceTarget,omitempty"`
+	RawMetric       *Nobl9RawMetric    `yaml:"rawMetric,omitempty"`
+	CountMetrics    *Nobl9CountMetrics `yaml:"countMetrics,omitempty"`
+}
+
+type Nobl9RawMetric struct {
+	Query Nobl9Query `yaml:"query"`
+}
+
+type Nobl9CountMetrics struct {
+	Incremental bool        `yaml:"incremental"`
+	Good        *Nobl9Query `yaml:"good,omitempty"`
+	Bad         *Nobl9Query `yaml:"bad,omitempty"`
+	Total       Nobl9Query  `yaml:"total"`
+}
+
+type Nobl9Query struct {
+	Prometheus *Nobl9PrometheusQuery `yaml:"prometheus,omitempty"`
+	Datadog    *Nobl9DatadogQuery    `yaml:"datadog,omitempty"`
+	CloudWatch *Nobl9CloudWatchQuery `yaml:"cloudWatch,omitempty"`
+}
+
+type Nobl9PrometheusQuery struct {
+	Promql string `yaml:"promql"`
+}
+
+type Nobl9DatadogQuery struct {
+	Query string `yaml:"query"`
+}
+
+type Nobl9CloudWatchQuery struct {
+	Region     string                     `yaml:"region"`
+	Namespace  string                     `yaml:"namespace,omitempty"`
+	MetricName string                     `yaml:"metricName,omitempty"`
+	Stat       string                     `yaml:"stat,omitempty"`
+	Dimensions []Nobl9CloudWatchDimension `yaml:"dimensions,omitempty"`
+	Sql        string                     `yaml:"sql,omitempty"`
+	Json       string                     `yaml:"json,omitempty"`
+}
+
+type Nobl9CloudWatchDimension struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type Nobl9AlertPolicySpec struct {
+	Description string                `yaml:"description,omitempty"`
+	Severity    string                `yaml:"severity"`
+	Conditions  []Nobl9AlertCondition `yaml:"conditions"`
+}
+
+type Nobl9AlertCondition struct {
+	Measurement    string  `yaml:"measurement"`
+	Value          float64 `yaml:"value"`
+	Op             string  `yaml:"op"`
+	AlertingWindow string  `yaml:"alertingWindow,omitempty"`
+}
+
+// RenderNobl9Manifests renders the Nobl9 Project, Service, AlertPolicy and SLO manifests of the SLOs with a prometheus,
+// datadog or cloudwatch metric source, keyed by "project/kind/name". Projects are the OpenSLO namespaces, services and
+// alert policies are rendered in every project of their SLOs. Objects that cannot be converted produce a warning naming
+// the field.
+func (d *OpenSloDataSource) RenderNobl9Manifests(diagnostics *diag.Diagnostics) error {
+	manifests := map[string]Nobl9Manifest{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		if !isNobl9Slo(slo) {
+			continue
+		}
+		project := nobl9Project(slo.Metadata)
+
+		var alertPolicies []string
+		var addedPolicies []string
+		for i, policy := range slo.AlertPolicies {
+			policyName := AlertPolicyName(sloName, i, policy)
+			key := nobl9ManifestKey(project, "AlertPolicy", policyName)
+			if _, ok := manifests[key]; !ok {
+				manifest, err := Nobl9AlertPolicy(policyName, project, policy)
+				if err != nil {
+					diagnostics.AddWarning("Cannot convert to nobl9, skipping", fmt.Sprintf("alert policy %s: %s", policyName, err.Error()))
+					continue
+				}
+				manifests[key] = *manifest
+				addedPolicies = append(addedPolicies, key)
+			}
+			alertPolicies = append(alertPolicies, KubernetesName(policyName))
+		}
+
+		manifest, err := Nobl9Slo(sloName, project, slo, alertPolicies)
+		if err != nil {
+			diagnostics.AddWarning("Cannot convert to nobl9, skipping", fmt.Sprintf("slo %s: %s", sloName, err.Error()))
+			for _, key := range addedPolicies {
+				delete(manifests, key)
+			}
+			continue
+		}
+		manifests[nobl9ManifestKey(project, "SLO", sloName)] = *manifest
+		manifests[nobl9ManifestKey(project, "Service", slo.ServiceRef)] = nobl9Manifest("Service", slo.ServiceRef, project, slo.Service.Metadata, Nobl9DescriptionSpec{Description: slo.Service.Description})
+		if _, ok := manifests[nobl9ManifestKey(project, "Project", project)]; !ok {
+			manifests[nobl9ManifestKey(project, "Project", project)] = nobl9Manifest("Project", project, "", MetadataModel{}, Nobl9DescriptionSpec{})
+		}
+	}
+
+	d.Nobl9_manifests = map[string]string{}
+	for key, manifest := range manifests {
+		manifestYaml, err := yaml.Marshal(manifest)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		d.Nobl9_manifests[key] = string(manifestYaml)
+	}
+	return nil
+}
+
+// Nobl9Slo returns the SLO manifest. Nobl9 SLOs have a single metric source, the datasource referenced by every
+// metric of the SLO, and need a service.
+func Nobl9Slo(sloName string, project string, slo SLOModel, alertPolicies []string) (*Nobl9Manifest, error) {
+	if slo.ServiceRef == "" {
+		return nil, fmt.Errorf("spec.service is required by nobl9")
+	}
+	timeWindow := TimeWindowModel{Duration: DEFAULT_SLO_TIME_WINDOW, IsRolling: true}
+	if len(slo.TimeWindow) > 0 {
+		timeWindow = slo.TimeWindow[0]
+	}
+	nobl9TimeWindow, err := Nobl9TimeWindowOf(timeWindow)
+	if err != nil {
+		return nil, fmt.Errorf("spec.timeWindow[0]: %w", err)
+	}
+
+	spec := Nobl9SloSpec{
+		Description:     slo.Description,
+		Service:         KubernetesName(slo.ServiceRef),
+		TimeWindows:     []Nobl9TimeWindow{*nobl9TimeWindow},
+		BudgetingMethod: slo.BudgetingMethod,
+		AlertPolicies:   alertPolicies,
+	}
+	metricSourceRef := ""
+	for i, objective := range slo.Objectives {
+		path := "spec.indicator"
+		if objective.IndicatorRef != "" || objective.Indicator.Metadata.Name != "" {
+			path = fmt.Sprintf("spec.objectives[%d].indicator", i)
+		}
+		indicator := ObjectiveIndicator(slo, objective)
+		if indicator.SyntheticMonitorRef != "" {
+			return nil, fmt.Errorf("%s.syntheticMonitorRef is not supported by nobl9, synthetic indicators are measured from blackbox_exporter probes", path)
+		}
+		indicatorSourceRef, err := nobl9MetricSourceRef(path, indicator)
+		if err != nil {
+			return nil, err
+		}
+		if metricSourceRef != "" && indicatorSourceRef != metricSourceRef {
+			return nil, fmt.Errorf("%s: every metric must use the same datasource for nobl9, got %s and %s", path, metricSourceRef, indicatorSourceRef)
+		}
+		metricSourceRef = indicatorSourceRef
+
+		nobl9Objective, err := Nobl9ObjectiveOf(path, i, slo.BudgetingMethod, objective, indicator)
+		if err != nil {
+			return nil, err
+		}
+		spec.Objectives = append(spec.Objectives, *nobl9Objective)
+	}
+	if len(spec.Objectives) == 0 {
+		return nil, fmt.Errorf("spec.objectives is required by nobl9")
+	}
+	spec.Indicator = Nobl9Indicator{MetricSource: Nobl9MetricSourceRef{Name: KubernetesName(metricSourceRef), Project: project, Kind: NOBL9_METRIC_SOURCE_AGENT}}
+
+	manifest := nobl9Manifest("SLO", sloName, project, slo.Metadata, spec)
+	return &manifest, nil
+}
+
+// Nobl9ObjectiveOf returns the objective with its queries: count metrics for ratio SLIs, a raw metric for threshold SLIs
+func Nobl9ObjectiveOf(path string, index int, budgetingMethod string, objective ObjectiveModel, indicator SLIModel) (*Nobl9Objective, error) {
+	name := KubernetesName(objective.DisplayName)
+	if name == "" {
+		name = "objective-" + strconv.Itoa(index+1)
+	}
+	nobl9Objective := Nobl9Objective{
+		Name:        name,
+		DisplayName: objective.DisplayName,
+		Target:      roundFloat(ObjectiveTarget(objective)),
+	}
+	if budgetingMethod == "Timeslices" {
+		if objective.TimeSliceTarget == 0 {
+			return nil, fmt.Errorf("spec.objectives[%d].timeSliceTarget is required by nobl9 for Timeslices", index)
+		}
+		nobl9Objective.TimeSliceTarget = objective.TimeSliceTarget
+	}
+
+	if isMetricSourceSet(indicator.ThresholdMetric.MetricSource) {
+		if _, ok := prometheusThresholdOps[objective.Op]; !ok {
+			return nil, fmt.Errorf("spec.objectives[%d].op must be one of lt, lte, gt or gte for a thresholdMetric, got %q", index, objective.Op)
+		}
+		query, err := Nobl9QueryOf(path+".thresholdMetric", indicator.ThresholdMetric.MetricSource)
+		if err != nil {
+			return nil, err
+		}
+		nobl9Objective.Op = objective.Op
+		nobl9Objective.Value = objective.Value
+		nobl9Objective.RawMetric = &Nobl9RawMetric{Query: *query}
+		return &nobl9Objective, nil
+	}
+
+	ratio := indicator.RatioMetric
+	if ratio.RawType != "" || isMetricSourceSet(ratio.Raw.MetricSource) {
+		return nil, fmt.Errorf("%s.ratioMetric.raw is not supported by nobl9", path)
+	}
+	if !isMetricSourceSet(ratio.Total.MetricSource) {
+		return nil, fmt.Errorf("%s.ratioMetric.total is required by nobl9", path)
+	}
+	total, err := Nobl9QueryOf(path+".ratioMetric.total", ratio.Total.MetricSource)
+	if err != nil {
+		return nil, err
+	}
+	countMetrics := Nobl9CountMetrics{Incremental: ratio.Counter, Total: *total}
+	if isMetricSourceSet(ratio.Good.MetricSource) {
+		if countMetrics.Good, err = Nobl9QueryOf(path+".ratioMetric.good", ratio.Good.MetricSource); err != nil {
+			return nil, err
+		}
+	} else if isMetricSourceSet(ratio.Bad.MetricSource) {
+		if countMetrics.Bad, err = Nobl9QueryOf(path+".ratioMetric.bad", ratio.Bad.MetricSource); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("%s.ratioMetric needs a good or a bad metric for nobl9", path)
+	}
+	// Nobl9 objectives are identified by their value
+	nobl9Objective.Value = float64(index + 1)
+	nobl9Objective.CountMetrics = &countMetrics
+	return &nobl9Objective, nil
+}
+
+// Nobl9QueryOf maps the metric source spec to the nobl9 query of its datasource type
+func Nobl9QueryOf(path string, metricSource MetricSource) (*Nobl9Query, error) {
+	spec := metricSource.Spec
+	switch strings.ToLower(metricSource.Type) {
+	case "prometheus":
+		query := strings.TrimSpace(spec.Scalar("query"))
+		if query == "" {
+			return nil, fmt.Errorf("%s.metricSource.spec.query is required", path)
+		}
+		if strings.Contains(query, PROMETHEUS_WINDOW_PLACEHOLDER) {
+			return nil, fmt.Errorf("%s.metricSource.spec.query: the %s placeholder is not supported by nobl9", path, PROMETHEUS_WINDOW_PLACEHOLDER)
+		}
+		return &Nobl9Query{Prometheus: &Nobl9PrometheusQuery{Promql: query}}, nil
+	case "datadog":
+		query := strings.TrimSpace(spec.Scalar("query"))
+		if query == "" {
+			return nil, fmt.Errorf("%s.metricSource.spec.query is required", path)
+		}
+		return &Nobl9Query{Datadog: &Nobl9DatadogQuery{Query: query}}, nil
+	case "cloudwatch":
+		cloudWatch := Nobl9CloudWatchQuery{
+			Region: spec.Scalar("region"),
+			Sql:    spec.Scalar("sql"),
+			Json:   spec.Scalar("json"),
+		}
+		if cloudWatch.Region == "" {
+			return nil, fmt.Errorf("%s.metricSource.spec.region is required", path)
+		}
+		if cloudWatch.Sql == "" && cloudWatch.Json == "" {
+			cloudWatch.Namespace = spec.Scalar("namespace")
+			cloudWatch.MetricName = spec.Scalar("metricName")
+			cloudWatch.Stat = spec.Scalar("stat")
+			for _, field := range []struct {
+				name  string
+				value string
+			}{{"namespace", cloudWatch.Namespace}, {"metricName", cloudWatch.MetricName}, {"stat", cloudWatch.Stat}} {
+				if field.value == "" {
+					return nil, fmt.Errorf("%s.metricSource.spec.%s is required without sql or json", path, field.name)
+				}
+			}
+			dimensions, err := nobl9CloudWatchDimensions(spec["dimensions"])
+			if err != nil {
+				return nil, fmt.Errorf("%s.metricSource.spec.dimensions: %w", path, err)
+			}
+			cloudWatch.Dimensions = dimensions
+		}
+		return &Nobl9Query{CloudWatch: &cloudWatch}, nil
+	}
+	return nil, fmt.Errorf("%s.metricSource.type %q is not supported, expected prometheus, datadog or cloudwatch", path, metricSource.Type)
+}
+
+func nobl9CloudWatchDimensions(value interface{}) ([]Nobl9CloudWatchDimension, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := normalizeFreeformValue(value).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of name and value")
+	}
+	var dimensions []Nobl9CloudWatchDimension
+	for i, item := range items {
+		dimension, ok := item.(map[string]interface{})
+		if !ok || dimension["name"] == nil || dimension["value"] == nil {
+			return nil, fmt.Errorf("item %d: expected a name and a value", i)
+		}
+		dimensions = append(dimensions, Nobl9CloudWatchDimension{Name: fmt.Sprint(dimension["name"]), Value: fmt.Sprint(dimension["value"])})
+	}
+	return dimensions, nil
+}
+
+// nobl9MetricSourceRef returns the datasource referenced by every metric of the indicator
+func nobl9MetricSourceRef(path string, sli SLIModel) (string, error) {
+	ref := ""
+	for _, metric := range []struct {
+		name         string
+		metricSource MetricSource
+	}{
+		{"thresholdMetric", sli.ThresholdMetric.MetricSource},
+		{"ratioMetric.good", sli.RatioMetric.Good.MetricSource},
+		{"ratioMetric.bad", sli.RatioMetric.Bad.MetricSource},
+		{"ratioMetric.total", sli.RatioMetric.Total.MetricSource},
+		{"ratioMetric.raw", sli.RatioMetric.Raw.MetricSource},
+	} {
+		if !isMetricSourceSet(metric.metricSource) {
+			continue
+		}
+		if metric.metricSource.MetricSourceRef == "" {
+			return "", fmt.Errorf("%s.%s.metricSource.metricSourceRef is required by nobl9, it names the nobl9 agent", path, metric.name)
+		}
+		if ref != "" && ref != metric.metricSource.MetricSourceRef {
+			return "", fmt.Errorf("%s.%s.metricSource.metricSourceRef: every metric must use the same datasource for nobl9, got %s and %s", path, metric.name, ref, metric.metricSource.MetricSourceRef)
+		}
+		ref = metric.metricSource.MetricSourceRef
+	}
+	if ref == "" {
+		return "", fmt.Errorf("%s has no metric", path)
+	}
+	return ref, nil
+}
+
+// Nobl9AlertPolicy returns the alert policy manifest, with the highest severity of its burnrate conditions
+func Nobl9AlertPolicy(policyName string, project string, policy AlertPolicyModel) (*Nobl9Manifest, error) {
+	spec := Nobl9AlertPolicySpec{Description: policy.Description}
+	severityRank := len(nobl9Severities)
+	for i, condition := range policy.Conditions {
+		if !strings.EqualFold(condition.Condition.Kind, ALERT_CONDITION_KIND_BURN_RATE) {
+			return nil, fmt.Errorf("spec.conditions[%d].condition.kind %q is not supported, expected burnrate", i, condition.Condition.Kind)
+		}
+		rank := nobl9SeverityRank(condition.Severity)
+		if rank < 0 {
+			return nil, fmt.Errorf("spec.conditions[%d].severity %q is not supported, expected high, medium or low (or critical, page, warning, ticket, info)", i, condition.Severity)
+		}
+		if rank < severityRank {
+			severityRank = rank
+		}
+		op := condition.Condition.Op
+		if op == "" {
+			op = DEFAULT_BURN_RATE_OP
+		}
+		if _, ok := prometheusThresholdOps[op]; !ok {
+			return nil, fmt.Errorf("spec.conditions[%d].condition.op must be one of lt, lte, gt or gte, got %q", i, condition.Condition.Op)
+		}
+		spec.Conditions = append(spec.Conditions, Nobl9AlertCondition{
+			Measurement:    NOBL9_BURN_RATE,
+			Value:          condition.Condition.Threshold,
+			Op:             op,
+			AlertingWindow: condition.Condition.LookbackWindow,
+		})
+	}
+	if len(spec.Conditions) == 0 {
+		return nil, fmt.Errorf("spec.conditions is required by nobl9")
+	}
+	spec.Severity = nobl9Severities[severityRank].severity
+
+	manifest := nobl9Manifest("AlertPolicy", policyName, project, policy.Metadata, spec)
+	return &manifest, nil
+}
+
+// Nobl9TimeWindowOf maps the time window duration to a nobl9 unit and count
+func Nobl9TimeWindowOf(timeWindow TimeWindowModel) (*Nobl9TimeWindow, error) {
+	match := openSloDurationRegex.FindStringSubmatch(timeWindow.Duration)
+	if match == nil {
+		return nil, fmt.Errorf("duration: bad duration %q", timeWindow.Duration)
+	}
+	unit, ok := nobl9TimeUnits[match[2]]
+	if !ok {
+		return nil, fmt.Errorf("duration: unit %s is not supported by nobl9", match[2])
+	}
+	count, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("duration: %w", err)
+	}
+	nobl9TimeWindow := Nobl9TimeWindow{Unit: unit, Count: count, IsRolling: timeWindow.IsRolling}
+	if !timeWindow.IsRolling {
+		if timeWindow.Calendar.StartTime == "" || timeWindow.Calendar.TimeZone == "" {
+			return nil, fmt.Errorf("calendar.startTime and calendar.timeZone are required by nobl9 for calendar time windows")
+		}
+		nobl9TimeWindow.Calendar = &Nobl9Calendar{StartTime: timeWindow.Calendar.StartTime, TimeZone: timeWindow.Calendar.TimeZone}
+	}
+	return &nobl9TimeWindow, nil
+}
+
+// isNobl9Slo returns true if an objective of the SLO reads a metric source type with a nobl9 query mapping
+func isNobl9Slo(slo SLOModel) bool {
+	for _, objective := range slo.Objectives {
+		if containsString(nobl9SourceTypes, indicatorMetricSourceType(ObjectiveIndicator(slo, objective))) {
+			return true
+		}
+	}
+	return false
+}
+
+func nobl9SeverityRank(severity string) int {
+	for i, nobl9Severity := range nobl9Severities {
+		if containsString(nobl9Severity.aliases, strings.ToLower(severity)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func nobl9Project(metadata MetadataModel) string {
+	if metadata.Namespace != "" {
+		return KubernetesName(metadata.Namespace)
+	}
+	return NOBL9_DEFAULT_PROJECT
+}
+
+func nobl9ManifestKey(project string, kind string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", project, kind, name)
+}
+
+func nobl9Manifest(kind string, name string, project string, metadata MetadataModel, spec interface{}) Nobl9Manifest {
+	var labels map[string][]string
+	if len(metadata.Labels) > 0 {
+		labels = map[string][]string{}
+		for key, value := range metadata.Labels {
+			labels[key] = []string{value}
+		}
+	}
+	return Nobl9Manifest{
+		ApiVersion: NOBL9_API_VERSION,
+		Kind:       kind,
+		Metadata: Nobl9Metadata{
+			Name:        KubernetesName(name),
+			DisplayName: metadata.DisplayName,
+			Project:     project,
+			Labels:      labels,
+		},
+		Spec: spec,
+	}
+}
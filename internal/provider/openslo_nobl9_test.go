@@ -0,0 +1,427 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const nobl9YamlSpec = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: prometheus-agent
+spec:
+  type: prometheus
+---
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: cloudwatch-agent
+spec:
+  type: cloudwatch
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+  namespace: shop
+spec:
+  description: Checkout service
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        metricSourceRef: prometheus-agent
+        spec:
+          query: sum(rate(http_requests_total{code=~"5.."}[1m]))
+    total:
+      metricSource:
+        metricSourceRef: prometheus-agent
+        spec:
+          query: sum(rate(http_requests_total[1m]))
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: latency
+spec:
+  thresholdMetric:
+    metricSource:
+      metricSourceRef: cloudwatch-agent
+      spec:
+        region: eu-west-1
+        namespace: AWS/ApplicationELB
+        metricName: TargetResponseTime
+        stat: p99
+        dimensions:
+        - name: LoadBalancer
+          value: app/checkout
+---
+apiVersion: openslo/v1
+kind: AlertCondition
+metadata:
+  name: fast-burn
+spec:
+  severity: page
+  condition:
+    kind: burnrate
+    op: gte
+    threshold: 14.4
+    lookbackWindow: 1h
+---
+apiVersion: openslo/v1
+kind: AlertCondition
+metadata:
+  name: slow-burn
+spec:
+  severity: ticket
+  condition:
+    kind: burnrate
+    threshold: 1
+    lookbackWindow: 3d
+---
+apiVersion: openslo/v1
+kind: AlertPolicy
+metadata:
+  name: on-call
+spec:
+  conditions:
+  - conditionRef: fast-burn
+  - conditionRef: slow-burn
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+  namespace: shop
+  displayName: Checkout availability
+  labels:
+    team: payments
+spec:
+  service: checkout
+  indicatorRef: errors
+  timeWindow:
+  - duration: 28d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Available
+    target: 0.999
+  alertPolicies:
+  - alertPolicyRef: on-call
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-latency
+  namespace: shop
+spec:
+  service: checkout
+  indicatorRef: latency
+  timeWindow:
+  - duration: 1M
+    isRolling: false
+    calendar:
+      startTime: "2022-01-01 00:00:00"
+      timeZone: Europe/Paris
+  budgetingMethod: Timeslices
+  objectives:
+  - op: lte
+    value: 0.5
+    target: 0.99
+    timeSliceTarget: 0.95
+    timeSliceWindow: 1m
+`
+
+const nobl9ExpectedSlo = `apiVersion: n9/v1alpha
+kind: SLO
+metadata:
+  name: checkout-availability
+  displayName: Checkout availability
+  project: shop
+  labels:
+    team:
+    - payments
+spec:
+  service: checkout
+  indicator:
+    metricSource:
+      name: prometheus-agent
+      project: shop
+      kind: Agent
+  timeWindows:
+  - unit: Day
+    count: 28
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - name: available-e6744473
+    displayName: Available
+    value: 1.0
+    target: 0.999
+    countMetrics:
+      incremental: true
+      bad:
+        prometheus:
+          promql: sum(rate(http_requests_total{code=~"5.."}[1m]))
+      total:
+        prometheus:
+          promql: sum(rate(http_requests_total[1m]))
+  alertPolicies:
+  - on-call
+`
+
+func TestOpenSLONobl9Manifests_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(nobl9YamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := warningsWithSummary(diagnostics, "Cannot convert to nobl9, skipping"); len(warnings) > 0 {
+		t.Fatalf("Unexpected warnings: %v", warnings)
+	}
+
+	// and
+	if diff := deep.Equal(sortedKeys(openslo.Nobl9_manifests), []string{
+		"shop/AlertPolicy/on-call",
+		"shop/Project/shop",
+		"shop/SLO/checkout-availability",
+		"shop/SLO/checkout-latency",
+		"shop/Service/checkout",
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(openslo.Nobl9_manifests["shop/SLO/checkout-availability"], nobl9ExpectedSlo); diff != nil {
+		t.Error(diff)
+	}
+
+	// and the alert policy has the highest severity of its conditions
+	policy := openslo.Nobl9_manifests["shop/AlertPolicy/on-call"]
+	for _, expected := range []string{"severity: High", "measurement: averageBurnRate", "value: 14.4", "op: gte", "alertingWindow: 1h", "alertingWindow: 3d", "op: gt\n"} {
+		if !strings.Contains(policy, expected) {
+			t.Errorf("Expected alert policy to contain %q, got %s", expected, policy)
+		}
+	}
+
+	// and the threshold objective is a cloudwatch raw metric on a calendar window
+	latency := openslo.Nobl9_manifests["shop/SLO/checkout-latency"]
+	for _, expected := range []string{"unit: Month", "timeZone: Europe/Paris", "timeSliceTarget: 0.95", "op: lte", "metricName: TargetResponseTime", "region: eu-west-1", "- name: LoadBalancer"} {
+		if !strings.Contains(latency, expected) {
+			t.Errorf("Expected latency SLO to contain %q, got %s", expected, latency)
+		}
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+const nobl9ProjectsYamlSpec = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: prometheus-agent
+spec:
+  type: prometheus
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+spec:
+  description: Checkout service
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        metricSourceRef: prometheus-agent
+        spec:
+          query: http_requests_total{code=~"5.."}
+    total:
+      metricSource:
+        metricSourceRef: prometheus-agent
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo/v1
+kind: AlertPolicy
+metadata:
+  name: on-call
+spec:
+  conditions:
+  - kind: AlertCondition
+    spec:
+      severity: page
+      condition:
+        kind: burnrate
+        threshold: 14.4
+        lookbackWindow: 1h
+---
+apiVersion: openslo/v1
+kind: AlertPolicy
+metadata:
+  name: tickets
+spec:
+  conditions:
+  - kind: AlertCondition
+    spec:
+      severity: ticket
+      condition:
+        kind: burnrate
+        threshold: 1
+        lookbackWindow: 3d
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-eu
+  namespace: eu
+spec:
+  service: checkout
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+  alertPolicies:
+  - alertPolicyRef: on-call
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-us
+  namespace: us
+spec:
+  service: checkout
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+  alertPolicies:
+  - alertPolicyRef: on-call
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: orphan
+  namespace: us
+spec:
+  indicatorRef: errors
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+  alertPolicies:
+  - alertPolicyRef: tickets
+`
+
+func TestOpenSLONobl9Manifests_shouldbeValid_projects(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(nobl9ProjectsYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and shared services and alert policies are rendered in every project, without the policies of skipped SLOs
+	if diff := deep.Equal(sortedKeys(openslo.Nobl9_manifests), []string{
+		"eu/AlertPolicy/on-call",
+		"eu/Project/eu",
+		"eu/SLO/checkout-eu",
+		"eu/Service/checkout",
+		"us/AlertPolicy/on-call",
+		"us/Project/us",
+		"us/SLO/checkout-us",
+		"us/Service/checkout",
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if !strings.Contains(openslo.Nobl9_manifests["eu/Service/checkout"], "project: eu") {
+		t.Errorf("Expected the eu service in the eu project, got %s", openslo.Nobl9_manifests["eu/Service/checkout"])
+	}
+	if warnings := warningsWithSummary(diagnostics, "Cannot convert to nobl9, skipping"); len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "slo orphan") {
+		t.Errorf("Expected a warning for the orphan SLO, got %v", warnings)
+	}
+}
+
+func TestOpenSLONobl9Manifests_shouldbeWarning_unsupportedFields(t *testing.T) {
+	cases := []struct {
+		name     string
+		sli      SLIModel
+		expected string
+	}{
+		{
+			name: "unsupported source type",
+			sli: SLIModel{ThresholdMetric: MetricModel{MetricSource: MetricSource{
+				MetricSourceRef: "splunk", Type: "splunk", Spec: FreeformMap{"query": "index=main"},
+			}}},
+			expected: `spec.indicator.thresholdMetric.metricSource.type "splunk" is not supported`,
+		},
+		{
+			name: "missing cloudwatch region",
+			sli: SLIModel{ThresholdMetric: MetricModel{MetricSource: MetricSource{
+				MetricSourceRef: "cloudwatch", Type: "cloudwatch", Spec: FreeformMap{"sql": "SELECT 1"},
+			}}},
+			expected: "spec.indicator.thresholdMetric.metricSource.spec.region is required",
+		},
+		{
+			name: "missing metricSourceRef",
+			sli: SLIModel{ThresholdMetric: MetricModel{MetricSource: MetricSource{
+				Type: "prometheus", Spec: FreeformMap{"query": "up"},
+			}}},
+			expected: "spec.indicator.thresholdMetric.metricSource.metricSourceRef is required",
+		},
+	}
+
+	for _, c := range cases {
+		// given
+		slo := SLOModel{
+			ServiceRef:      "checkout",
+			BudgetingMethod: "Occurrences",
+			Indicator:       c.sli,
+			Objectives:      []ObjectiveModel{{Op: "lt", Value: 1, Target: 0.99}},
+		}
+
+		// when
+		_, err := Nobl9Slo("checkout", NOBL9_DEFAULT_PROJECT, slo, nil)
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("%s: expected an error naming %q, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestOpenSLONobl9AlertPolicy_shouldbeError_unsupportedCondition(t *testing.T) {
+	// given
+	policy := AlertPolicyModel{Conditions: []AlertConditionModel{
+		{Severity: "page", Condition: AlertConditionModelCondition{Kind: "threshold", Threshold: 1}},
+	}}
+
+	// when
+	_, err := Nobl9AlertPolicy("on-call", NOBL9_DEFAULT_PROJECT, policy)
+
+	// then
+	if err == nil || !strings.Contains(err.Error(), `spec.conditions[0].condition.kind "threshold"`) {
+		t.Errorf("Expected an error naming the condition kind, got %v", err)
+	}
+}
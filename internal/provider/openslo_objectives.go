@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ObjectiveKeys returns the key of every objective of the SLO, in the objectives order.
+// Objectives are keyed by "slo/displayName", or "slo/index" when they have no unique display name. A display name
+// equal to the index of an objective would collide with its key, such objectives are keyed by index too.
+func ObjectiveKeys(sloName string, slo SLOModel) []string {
+	displayNames := map[string]int{}
+	for i, objective := range slo.Objectives {
+		displayNames[objective.DisplayName]++
+		displayNames[strconv.Itoa(i)]++
+	}
+
+	keys := make([]string, len(slo.Objectives))
+	for i, objective := range slo.Objectives {
+		if objective.DisplayName != "" && displayNames[objective.DisplayName] == 1 {
+			keys[i] = fmt.Sprintf("%s/%s", sloName, objective.DisplayName)
+		} else {
+			keys[i] = fmt.Sprintf("%s/%s", sloName, strconv.Itoa(i))
+		}
+	}
+	return keys
+}
+
+// ObjectiveIndicator returns the indicator of the objective, falling back to the SLO one
+func ObjectiveIndicator(slo SLOModel, objective ObjectiveModel) SLIModel {
+	if objective.IndicatorRef != "" || objective.Indicator.Metadata.Name != "" {
+		return objective.Indicator
+	}
+	return slo.Indicator
+}
+
+// FlattenObjectives builds the objectives map, with one entry per objective of every SLO
+func (d *OpenSloDataSource) FlattenObjectives() {
+	d.Objectives = map[string]FlatObjectiveModel{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			objective := slo.Objectives[i]
+			d.Objectives[key] = FlatObjectiveModel{
+				Slo:             sloName,
+				Index:           int64(i),
+				Metadata:        slo.Metadata,
+				Description:     slo.Description,
+				Objective:       objective,
+				Service:         slo.Service,
+				ServiceRef:      slo.ServiceRef,
+				Indicator:       ObjectiveIndicator(slo, objective),
+				TimeWindow:      slo.TimeWindow,
+				BudgetingMethod: slo.BudgetingMethod,
+				AlertPolicies:   slo.AlertPolicies,
+			}
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const objectivesYamlSpec = `
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: my-service
+spec:
+  description: This service does blablabla
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: availability
+spec:
+  ratioMetric:
+    good:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total{code!~"5.."}
+    total:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: latency
+spec:
+  thresholdMetric:
+    metricSource:
+      type: prometheus
+      spec:
+        query: histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket[5m])) by (le))
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: my-slo
+spec:
+  service: my-service
+  indicatorRef: availability
+  timeWindow:
+  - duration: 28d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Good
+    target: 0.99
+  - target: 0.95
+    op: lt
+    value: 0.5
+    indicatorRef: latency
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: other-slo
+spec:
+  indicatorRef: availability
+  budgetingMethod: Timeslices
+  objectives:
+  - displayName: Same
+    target: 0.9
+  - displayName: Same
+    target: 0.8
+`
+
+func TestOpenSLOObjectives_shouldbeValid_flattened(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(objectivesYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Objectives), []string{"my-slo/1", "my-slo/Good", "other-slo/0", "other-slo/1"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	good := openslo.Objectives["my-slo/Good"]
+	if good.Slo != "my-slo" || good.Index != 0 || good.Objective.Target != 0.99 {
+		t.Errorf("Unexpected objective %+v", good)
+	}
+	if good.Service.Metadata.Name != "my-service" || good.ServiceRef != "my-service" {
+		t.Errorf("Expected service to be resolved, but got %+v", good.Service)
+	}
+	if good.Indicator.Metadata.Name != "availability" {
+		t.Errorf("Expected SLO indicator, but got %s", good.Indicator.Metadata.Name)
+	}
+	diff = deep.Equal(good.TimeWindow, []TimeWindowModel{{Duration: "28d", IsRolling: true}})
+	if diff != nil {
+		t.Error(diff)
+	}
+	if good.BudgetingMethod != "Occurrences" {
+		t.Errorf("Expected Occurrences, but got %s", good.BudgetingMethod)
+	}
+
+	// and the objective indicator takes precedence
+	latency := openslo.Objectives["my-slo/1"]
+	if latency.Indicator.Metadata.Name != "latency" || latency.Index != 1 {
+		t.Errorf("Expected objective indicator, but got %s", latency.Indicator.Metadata.Name)
+	}
+
+	// and
+	openSloState(t, &openslo)
+}
+
+func TestOpenSLOObjectives_shouldbeValid_indexLikeDisplayNameKeys(t *testing.T) {
+	// given
+	slo := SLOModel{
+		Objectives: []ObjectiveModel{
+			{DisplayName: "1", Target: 0.99},
+			{Target: 0.95},
+			{DisplayName: "7", Target: 0.9},
+		},
+	}
+
+	// when
+	keys := ObjectiveKeys("my-slo", slo)
+
+	// then
+	diff := deep.Equal(keys, []string{"my-slo/0", "my-slo/1", "my-slo/7"})
+	if diff != nil {
+		t.Error(diff)
+	}
+}
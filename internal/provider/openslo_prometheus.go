@@ -0,0 +1,513 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	PROMETHEUS_LABEL_SLO          = "openslo_slo"
+	PROMETHEUS_LABEL_OBJECTIVE    = "openslo_objective"
+	PROMETHEUS_LABEL_SERVICE      = "openslo_service"
+	PROMETHEUS_LABEL_WINDOW       = "openslo_window"
+	PROMETHEUS_LABEL_ALERT_POLICY = "openslo_alert_policy"
+	PROMETHEUS_WINDOW_PLACEHOLDER = "{{.window}}"
+	PROMETHEUS_ALERT_BURN_RATE    = "OpenSLOErrorBudgetBurn"
+	PROMETHEUS_ALERT_NO_DATA      = "OpenSLONoData"
+	PROMETHEUS_NO_DATA_FOR        = "10m"
+	DEFAULT_SLO_TIME_WINDOW       = "30d"
+)
+
+var prometheusLabelNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+type PrometheusRuleFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups"`
+}
+
+type PrometheusRuleGroup struct {
+	Name  string           `yaml:"name"`
+	Rules []PrometheusRule `yaml:"rules"`
+}
+
+type PrometheusRule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// BurnRateWindow is a multi-window multi-burn-rate alert, as described in the Google SRE workbook
+type BurnRateWindow struct {
+	Severity    string
+	BurnRate    float64
+	LongWindow  time.Duration
+	ShortWindow time.Duration
+	// Set when the window comes from a burnrate alert condition
+	AlertPolicy string
+	Op          string
+	AlertAfter  string
+}
+
+// The Google SRE workbook (and Sloth) recommended windows for a 30 days SLO
+var DefaultBurnRateWindows = []BurnRateWindow{
+	{Severity: "page", BurnRate: 14.4, LongWindow: time.Hour, ShortWindow: 5 * time.Minute},
+	{Severity: "page", BurnRate: 6, LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute},
+	{Severity: "ticket", BurnRate: 3, LongWindow: DAY, ShortWindow: 2 * time.Hour},
+	{Severity: "ticket", BurnRate: 1, LongWindow: 3 * DAY, ShortWindow: 6 * time.Hour},
+}
+
+var prometheusThresholdOps = map[string]string{
+	"lt":  "<",
+	"lte": "<=",
+	"gt":  ">",
+	"gte": ">=",
+}
+
+// RenderPrometheusRules renders the recording and alerting rules of every prometheus backed SLO
+func (d *OpenSloDataSource) RenderPrometheusRules(diagnostics *diag.Diagnostics) error {
+	d.Prometheus_rules = map[string]string{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		groups := PrometheusRuleGroups(sloName, d.Slos[sloName], diagnostics)
+		if len(groups) == 0 {
+			continue
+		}
+		rules, err := yaml.Marshal(PrometheusRuleFile{Groups: groups})
+		if err != nil {
+			return fmt.Errorf("slo %s: %w", sloName, err)
+		}
+		d.Prometheus_rules[sloName] = string(rules)
+	}
+	return nil
+}
+
+// IsPrometheusIndicator returns true if every metric source of the SLI is a prometheus one
+func IsPrometheusIndicator(sli SLIModel) bool {
+	return indicatorMetricSourceType(sli) == "prometheus"
+}
+
+// indicatorMetricSourceType returns the lowercase type of the SLI metric sources,
+// or an empty string if it has none or they don't share the same type
+func indicatorMetricSourceType(sli SLIModel) string {
+	sourceType := ""
+	for _, metricSource := range sli.metricSources() {
+		if !isMetricSourceSet(*metricSource) {
+			continue
+		}
+		if sourceType != "" && !strings.EqualFold(sourceType, metricSource.Type) {
+			return ""
+		}
+		sourceType = strings.ToLower(metricSource.Type)
+	}
+	return sourceType
+}
+
+func isMetricSourceSet(metricSource MetricSource) bool {
+	return metricSource.Type != "" || metricSource.MetricSourceRef != "" || len(metricSource.Spec) != 0
+}
+
+// ObjectiveTarget returns the objective target as a ratio, from target or targetPercent
+func ObjectiveTarget(objective ObjectiveModel) float64 {
+	if objective.Target == 0 && objective.TargetPercent != 0 {
+		return objective.TargetPercent / 100
+	}
+	return objective.Target
+}
+
+// SloTimeWindow returns the first time window duration of the SLO, defaulting to 30 days
+func SloTimeWindow(slo SLOModel) string {
+	if len(slo.TimeWindow) == 0 || slo.TimeWindow[0].Duration == "" {
+		return DEFAULT_SLO_TIME_WINDOW
+	}
+	return slo.TimeWindow[0].Duration
+}
+
+// PrometheusRuleGroups returns the recording and alerting rule groups of the prometheus backed objectives of the SLO.
+// Objectives that cannot be expressed in PromQL produce a warning.
+func PrometheusRuleGroups(sloName string, slo SLOModel, diagnostics *diag.Diagnostics) []PrometheusRuleGroup {
+	sliRecordings := PrometheusRuleGroup{Name: fmt.Sprintf("openslo-%s-sli-recordings", sloName)}
+	metaRecordings := PrometheusRuleGroup{Name: fmt.Sprintf("openslo-%s-meta-recordings", sloName)}
+	alerts := PrometheusRuleGroup{Name: fmt.Sprintf("openslo-%s-alerts", sloName)}
+
+	for i, key := range ObjectiveKeys(sloName, slo) {
+		objective := slo.Objectives[i]
+		indicator := ObjectiveIndicator(slo, objective)
+		if !IsPrometheusIndicator(indicator) {
+			continue
+		}
+
+		rules, err := prometheusObjectiveRules(sloName, slo, key, objective, indicator)
+		if err != nil {
+			diagnostics.AddWarning("Cannot render prometheus rules, skipping", fmt.Sprintf("objective %s: %s", key, err.Error()))
+			continue
+		}
+		sliRecordings.Rules = append(sliRecordings.Rules, rules.sli...)
+		metaRecordings.Rules = append(metaRecordings.Rules, rules.meta...)
+		alerts.Rules = append(alerts.Rules, rules.alerts...)
+	}
+
+	if len(sliRecordings.Rules) == 0 {
+		return nil
+	}
+	return []PrometheusRuleGroup{sliRecordings, metaRecordings, alerts}
+}
+
+type prometheusObjectiveRuleSet struct {
+	sli    []PrometheusRule
+	meta   []PrometheusRule
+	alerts []PrometheusRule
+}
+
+func prometheusObjectiveRules(sloName string, slo SLOModel, key string, objective ObjectiveModel, indicator SLIModel) (*prometheusObjectiveRuleSet, error) {
+	period, err := ParseOpenSloDuration(SloTimeWindow(slo))
+	if err != nil {
+		return nil, err
+	}
+	target := ObjectiveTarget(objective)
+	if target <= 0 || target >= 1 {
+		return nil, fmt.Errorf("target must be between 0 and 1, got %v", target)
+	}
+	errorBudget := 1 - target
+	burnRateWindows, err := objectiveBurnRateWindows(sloName, slo, key, objective)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := PrometheusObjectiveLabels(sloName, slo, key)
+	selector := PrometheusObjectiveSelector(sloName, key)
+	ruleSet := prometheusObjectiveRuleSet{}
+
+	// SLI error ratio over every window needed by the burn rate alerts
+	windows := []time.Duration{5 * time.Minute}
+	for _, window := range burnRateWindows {
+		for _, duration := range []time.Duration{window.LongWindow, window.ShortWindow} {
+			if !containsDuration(windows, duration) {
+				windows = append(windows, duration)
+			}
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	for _, window := range windows {
+		promWindow := FormatPrometheusDuration(window)
+		expr, err := PrometheusErrorRatio(indicator, objective, promWindow)
+		if err != nil {
+			return nil, err
+		}
+		ruleSet.sli = append(ruleSet.sli, PrometheusRule{
+			Record: PrometheusErrorRatioRecord(promWindow),
+			Expr:   expr,
+			Labels: mergeLabels(labels, map[string]string{PROMETHEUS_LABEL_WINDOW: promWindow}),
+		})
+	}
+	promPeriod := FormatPrometheusDuration(period)
+	if !containsDuration(windows, period) {
+		ruleSet.sli = append(ruleSet.sli, PrometheusRule{
+			Record: PrometheusErrorRatioRecord(promPeriod),
+			Expr:   fmt.Sprintf("avg_over_time(%s%s[%s])", PrometheusErrorRatioRecord("5m"), selector, promPeriod),
+			Labels: mergeLabels(labels, map[string]string{PROMETHEUS_LABEL_WINDOW: promPeriod}),
+		})
+	}
+
+	// Metadata, to build dashboards and alerts on
+	ruleSet.meta = []PrometheusRule{
+		{
+			Record: "slo:objective:ratio",
+			Expr:   fmt.Sprintf("vector(%s)", formatPrometheusFloat(target)),
+			Labels: labels,
+		},
+		{
+			Record: "slo:error_budget:ratio",
+			Expr:   fmt.Sprintf("vector(%s)", formatPrometheusFloat(errorBudget)),
+			Labels: labels,
+		},
+		{
+			Record: "slo:time_period:days",
+			Expr:   fmt.Sprintf("vector(%s)", formatPrometheusFloat(float64(period)/float64(DAY))),
+			Labels: labels,
+		},
+		{
+			Record: "slo:current_burn_rate:ratio",
+			Expr: fmt.Sprintf("%s%s\n/ on(%s, %s) group_left\nslo:error_budget:ratio%s",
+				PrometheusErrorRatioRecord("5m"), selector, PROMETHEUS_LABEL_SLO, PROMETHEUS_LABEL_OBJECTIVE, selector),
+			Labels: labels,
+		},
+		{
+			Record: "slo:period_burn_rate:ratio",
+			Expr: fmt.Sprintf("%s%s\n/ on(%s, %s) group_left\nslo:error_budget:ratio%s",
+				PrometheusErrorRatioRecord(promPeriod), selector, PROMETHEUS_LABEL_SLO, PROMETHEUS_LABEL_OBJECTIVE, selector),
+			Labels: labels,
+		},
+		{
+			Record: "slo:period_error_budget_remaining:ratio",
+			Expr:   fmt.Sprintf("1 - slo:period_burn_rate:ratio%s", selector),
+			Labels: labels,
+		},
+	}
+
+	// One alert per alert policy and severity, firing when any of its windows burns too fast
+	type alertGroup struct {
+		policy     string
+		severity   string
+		alertAfter string
+	}
+	var groups []alertGroup
+	windowsByGroup := map[alertGroup][]BurnRateWindow{}
+	for _, window := range burnRateWindows {
+		group := alertGroup{policy: window.AlertPolicy, severity: window.Severity, alertAfter: window.AlertAfter}
+		if _, ok := windowsByGroup[group]; !ok {
+			groups = append(groups, group)
+		}
+		windowsByGroup[group] = append(windowsByGroup[group], window)
+	}
+	for _, group := range groups {
+		var conditions []string
+		for _, window := range windowsByGroup[group] {
+			op, ok := prometheusThresholdOps[window.Op]
+			if !ok {
+				op = ">"
+			}
+			threshold := formatPrometheusFloat(window.BurnRate * errorBudget)
+			conditions = append(conditions, fmt.Sprintf("(\n  %s%s %s %s\n  and ignoring(%s)\n  %s%s %s %s\n)",
+				PrometheusErrorRatioRecord(FormatPrometheusDuration(window.LongWindow)), selector, op, threshold,
+				PROMETHEUS_LABEL_WINDOW,
+				PrometheusErrorRatioRecord(FormatPrometheusDuration(window.ShortWindow)), selector, op, threshold))
+		}
+		alertLabels := map[string]string{}
+		if group.severity != "" {
+			alertLabels["severity"] = group.severity
+		}
+		if group.policy != "" {
+			alertLabels[PROMETHEUS_LABEL_ALERT_POLICY] = group.policy
+		}
+		ruleSet.alerts = append(ruleSet.alerts, PrometheusRule{
+			Alert:  PROMETHEUS_ALERT_BURN_RATE,
+			Expr:   strings.Join(conditions, fmt.Sprintf("\nor ignoring(%s)\n", PROMETHEUS_LABEL_WINDOW)),
+			For:    group.alertAfter,
+			Labels: mergeLabels(labels, alertLabels),
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("SLO %s objective %s is burning its error budget too fast", sloName, key),
+			},
+		})
+	}
+
+	// No data alert, for the alert policies that want to be notified about it
+	if SloAlertsWhenNoData(slo) {
+		ruleSet.alerts = append(ruleSet.alerts, PrometheusRule{
+			Alert:  PROMETHEUS_ALERT_NO_DATA,
+			Expr:   fmt.Sprintf("absent(%s%s)", PrometheusErrorRatioRecord("5m"), selector),
+			For:    PROMETHEUS_NO_DATA_FOR,
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("SLO %s objective %s has no data", sloName, key),
+			},
+		})
+	}
+
+	return &ruleSet, nil
+}
+
+// SloAlertsWhenNoData returns true if any alert policy of the SLO alerts when there is no data
+func SloAlertsWhenNoData(slo SLOModel) bool {
+	for _, policy := range slo.AlertPolicies {
+		if policy.AlertWhenNoData {
+			return true
+		}
+	}
+	return false
+}
+
+// objectiveBurnRateWindows returns the windows of the objective burnrate alert conditions,
+// or the default ones when the SLO has none
+func objectiveBurnRateWindows(sloName string, slo SLOModel, key string, objective ObjectiveModel) ([]BurnRateWindow, error) {
+	alerts, err := ObjectiveBurnRateAlerts(sloName, slo, key, objective)
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return DefaultBurnRateWindows, nil
+	}
+	windows := make([]BurnRateWindow, len(alerts))
+	for i, alert := range alerts {
+		// Windows were validated when computing the alert
+		longWindow, _ := ParseOpenSloDuration(alert.LongWindow)
+		shortWindow, _ := ParseOpenSloDuration(alert.ShortWindow)
+		windows[i] = BurnRateWindow{
+			Severity:    alert.Severity,
+			BurnRate:    alert.BurnRate,
+			LongWindow:  longWindow,
+			ShortWindow: shortWindow,
+			AlertPolicy: alert.AlertPolicy,
+			Op:          alert.Op,
+			AlertAfter:  alert.AlertAfter,
+		}
+	}
+	return windows, nil
+}
+
+// PrometheusErrorRatioRecord returns the name of the error ratio recording rule over window
+func PrometheusErrorRatioRecord(window string) string {
+	return "slo:sli_error:ratio_rate" + window
+}
+
+// PrometheusObjectiveLabels returns the labels set on every rule of an objective. SLO label names are converted to
+// Prometheus label names, see PrometheusLabelName.
+func PrometheusObjectiveLabels(sloName string, slo SLOModel, key string) map[string]string {
+	sloLabels := map[string]string{}
+	for _, name := range sortedKeys(slo.Metadata.Labels) {
+		labelName := PrometheusLabelName(name)
+		if _, ok := sloLabels[labelName]; !ok {
+			sloLabels[labelName] = slo.Metadata.Labels[name]
+		}
+	}
+	labels := mergeLabels(sloLabels, map[string]string{
+		PROMETHEUS_LABEL_SLO:       sloName,
+		PROMETHEUS_LABEL_OBJECTIVE: strings.TrimPrefix(key, sloName+"/"),
+	})
+	if slo.ServiceRef != "" {
+		labels[PROMETHEUS_LABEL_SERVICE] = slo.ServiceRef
+	}
+	return labels
+}
+
+// PrometheusLabelName returns the name as a valid Prometheus label name, matching [a-zA-Z_][a-zA-Z0-9_]*: invalid
+// characters are replaced by underscores, e.g. app.kubernetes.io/name becomes app_kubernetes_io_name
+func PrometheusLabelName(name string) string {
+	labelName := prometheusLabelNameInvalidChars.ReplaceAllString(name, "_")
+	if labelName == "" || (labelName[0] >= '0' && labelName[0] <= '9') {
+		labelName = "_" + labelName
+	}
+	return labelName
+}
+
+// PrometheusObjectiveSelector returns the series selector matching the rules of an objective
+func PrometheusObjectiveSelector(sloName string, key string) string {
+	return fmt.Sprintf("{%s=%q, %s=%q}", PROMETHEUS_LABEL_SLO, sloName, PROMETHEUS_LABEL_OBJECTIVE, strings.TrimPrefix(key, sloName+"/"))
+}
+
+// PrometheusErrorRatio returns the PromQL expression of the SLI error ratio over window
+func PrometheusErrorRatio(sli SLIModel, objective ObjectiveModel, window string) (string, error) {
+	ratio := sli.RatioMetric
+	if ratio.RawType != "" || isMetricSourceSet(ratio.Raw.MetricSource) {
+		raw, err := prometheusWindowedQuery(ratio.Raw.MetricSource, window, "avg(avg_over_time(%s[%s]))")
+		if err != nil {
+			return "", fmt.Errorf("ratioMetric.raw: %w", err)
+		}
+		switch ratio.RawType {
+		case "success":
+			return fmt.Sprintf("1 - (%s)", raw), nil
+		case "failure":
+			return raw, nil
+		}
+		return "", fmt.Errorf("ratioMetric.rawType must be success or failure, got %q", ratio.RawType)
+	}
+
+	if isMetricSourceSet(ratio.Good.MetricSource) || isMetricSourceSet(ratio.Bad.MetricSource) || isMetricSourceSet(ratio.Total.MetricSource) {
+		aggregation := "sum(avg_over_time(%s[%s]))"
+		if ratio.Counter {
+			aggregation = "sum(rate(%s[%s]))"
+		}
+		var good, bad, total string
+		var err error
+		if isMetricSourceSet(ratio.Good.MetricSource) {
+			if good, err = prometheusWindowedQuery(ratio.Good.MetricSource, window, aggregation); err != nil {
+				return "", fmt.Errorf("ratioMetric.good: %w", err)
+			}
+		}
+		if isMetricSourceSet(ratio.Bad.MetricSource) {
+			if bad, err = prometheusWindowedQuery(ratio.Bad.MetricSource, window, aggregation); err != nil {
+				return "", fmt.Errorf("ratioMetric.bad: %w", err)
+			}
+		}
+		if isMetricSourceSet(ratio.Total.MetricSource) {
+			if total, err = prometheusWindowedQuery(ratio.Total.MetricSource, window, aggregation); err != nil {
+				return "", fmt.Errorf("ratioMetric.total: %w", err)
+			}
+		}
+		switch {
+		case bad != "" && total != "":
+			return fmt.Sprintf("(%s)\n/\n(%s)", bad, total), nil
+		case good != "" && total != "":
+			return fmt.Sprintf("1 - (\n  (%s)\n  /\n  (%s)\n)", good, total), nil
+		case good != "" && bad != "":
+			return fmt.Sprintf("(%s)\n/\n((%s) + (%s))", bad, good, bad), nil
+		}
+		return "", fmt.Errorf("ratioMetric needs a total, or both good and bad metrics")
+	}
+
+	if isMetricSourceSet(sli.ThresholdMetric.MetricSource) {
+		op, ok := prometheusThresholdOps[objective.Op]
+		if !ok {
+			return "", fmt.Errorf("thresholdMetric needs an objective op of lt, lte, gt or gte, got %q", objective.Op)
+		}
+		query, err := prometheusQuery(sli.ThresholdMetric.MetricSource, window)
+		if err != nil {
+			return "", fmt.Errorf("thresholdMetric: %w", err)
+		}
+		return fmt.Sprintf("1 - avg_over_time(((%s) %s bool %s)[%s:])", query, op, formatPrometheusFloat(objective.Value), window), nil
+	}
+
+	return "", fmt.Errorf("indicator has no metric")
+}
+
+// prometheusQuery returns the metric source query, with the window placeholder replaced
+func prometheusQuery(metricSource MetricSource, window string) (string, error) {
+	query := strings.TrimSpace(metricSource.Spec.Scalar("query"))
+	if query == "" {
+		return "", fmt.Errorf("metricSource.spec.query is required")
+	}
+	return strings.ReplaceAll(query, PROMETHEUS_WINDOW_PLACEHOLDER, window), nil
+}
+
+// prometheusWindowedQuery returns the metric source query over window. Queries using the {{.window}}
+// placeholder are used as is, any other query is expected to be a series selector and is aggregated.
+func prometheusWindowedQuery(metricSource MetricSource, window string, aggregation string) (string, error) {
+	query, err := prometheusQuery(metricSource, window)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(metricSource.Spec.Scalar("query"), PROMETHEUS_WINDOW_PLACEHOLDER) {
+		return query, nil
+	}
+	return fmt.Sprintf(aggregation, query, window), nil
+}
+
+func mergeLabels(labelSets ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, labels := range labelSets {
+		for k, v := range labels {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func containsDuration(durations []time.Duration, duration time.Duration) bool {
+	for _, d := range durations {
+		if d == duration {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPrometheusFloat formats a float without the rounding noise of float arithmetic (1 - 0.999)
+func formatPrometheusFloat(value float64) string {
+	return strconv.FormatFloat(roundFloat(value), 'f', -1, 64)
+}
+
+// roundFloat rounds a float to 12 significant digits
+func roundFloat(value float64) float64 {
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 12, 64), 64)
+	if err != nil {
+		return value
+	}
+	return rounded
+}
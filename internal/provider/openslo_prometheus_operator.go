@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	PROMETHEUS_OPERATOR_API_VERSION = "monitoring.coreos.com/v1"
+	PROMETHEUS_OPERATOR_RULE_KIND   = "PrometheusRule"
+	KUBERNETES_LABEL_MANAGED_BY     = "app.kubernetes.io/managed-by"
+	KUBERNETES_MANAGED_BY_OPENSLO   = "terraform-provider-openslo"
+)
+
+type PrometheusOperatorRule struct {
+	ApiVersion string                     `yaml:"apiVersion"`
+	Kind       string                     `yaml:"kind"`
+	Metadata   KubernetesObjectMetadata   `yaml:"metadata"`
+	Spec       PrometheusOperatorRuleSpec `yaml:"spec"`
+}
+
+type KubernetesObjectMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type PrometheusOperatorRuleSpec struct {
+	Groups []PrometheusRuleGroup `yaml:"groups"`
+}
+
+// RenderPrometheusRuleManifests renders a prometheus-operator PrometheusRule per service, keyed by service, with the
+// rule groups of the prometheus backed SLOs of the service. SLOs without a service are only in prometheus_rules.
+func (d *OpenSloDataSource) RenderPrometheusRuleManifests() error {
+	d.Prometheus_rule_manifests = map[string]string{}
+	groupsByService := map[string][]PrometheusRuleGroup{}
+	services := map[string]ServiceModel{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		if slo.ServiceRef == "" {
+			continue
+		}
+		// Warnings were already reported when rendering prometheus_rules
+		groups := PrometheusRuleGroups(sloName, slo, &diag.Diagnostics{})
+		groupsByService[slo.ServiceRef] = append(groupsByService[slo.ServiceRef], groups...)
+		services[slo.ServiceRef] = slo.Service
+	}
+
+	for _, serviceName := range sortedKeys(groupsByService) {
+		groups := groupsByService[serviceName]
+		if len(groups) == 0 {
+			continue
+		}
+		manifest, err := yaml.Marshal(PrometheusOperatorRuleOf(serviceName, services[serviceName], groups))
+		if err != nil {
+			return fmt.Errorf("service %s: %w", serviceName, err)
+		}
+		d.Prometheus_rule_manifests[serviceName] = string(manifest)
+	}
+	return nil
+}
+
+// PrometheusOperatorRuleOf returns the PrometheusRule of the service rule groups. The name, namespace, labels and
+// annotations are the ones of the service metadata, with the openslo service and managed-by labels.
+func PrometheusOperatorRuleOf(serviceName string, service ServiceModel, groups []PrometheusRuleGroup) PrometheusOperatorRule {
+	return PrometheusOperatorRule{
+		ApiVersion: PROMETHEUS_OPERATOR_API_VERSION,
+		Kind:       PROMETHEUS_OPERATOR_RULE_KIND,
+		Metadata: KubernetesObjectMetadata{
+			Name:      KubernetesName("openslo-" + serviceName),
+			Namespace: service.Metadata.Namespace,
+			Labels: mergeLabels(service.Metadata.Labels, map[string]string{
+				PROMETHEUS_LABEL_SERVICE:    serviceName,
+				KUBERNETES_LABEL_MANAGED_BY: KUBERNETES_MANAGED_BY_OPENSLO,
+			}),
+			Annotations: service.Metadata.Annotations,
+		},
+		Spec: PrometheusOperatorRuleSpec{Groups: groups},
+	}
+}
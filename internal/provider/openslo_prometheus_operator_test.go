@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestOpenSLOPrometheusRuleManifests_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(prometheusYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(sortedKeys(openslo.Prometheus_rule_manifests), []string{"checkout"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// and
+	manifest := PrometheusOperatorRule{}
+	if err := yaml.Unmarshal([]byte(openslo.Prometheus_rule_manifests["checkout"]), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(manifest.Metadata, KubernetesObjectMetadata{
+		Name: "openslo-checkout",
+		Labels: map[string]string{
+			PROMETHEUS_LABEL_SERVICE:    "checkout",
+			KUBERNETES_LABEL_MANAGED_BY: KUBERNETES_MANAGED_BY_OPENSLO,
+		},
+	}); diff != nil {
+		t.Error(diff)
+	}
+	if manifest.ApiVersion != "monitoring.coreos.com/v1" || manifest.Kind != "PrometheusRule" {
+		t.Errorf("Unexpected apiVersion and kind %s %s", manifest.ApiVersion, manifest.Kind)
+	}
+
+	// and the groups are the prometheus_rules groups of every SLO of the service
+	var expected []PrometheusRuleGroup
+	for _, sloName := range sortedKeys(openslo.Prometheus_rules) {
+		rules := PrometheusRuleFile{}
+		if err := yaml.Unmarshal([]byte(openslo.Prometheus_rules[sloName]), &rules); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, rules.Groups...)
+	}
+	if diff := deep.Equal(manifest.Spec.Groups, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// and state can be serialized
+	openSloState(t, &openslo)
+}
+
+func TestOpenSLOPrometheusOperatorRuleOf_shouldUse_serviceMetadata(t *testing.T) {
+	// given
+	service := ServiceModel{Metadata: MetadataModel{
+		Name:        "Checkout_API",
+		Namespace:   "shop",
+		Labels:      map[string]string{"prometheus": "k8s", PROMETHEUS_LABEL_SERVICE: "overridden"},
+		Annotations: map[string]string{"owner": "payments"},
+	}}
+
+	// when
+	manifest := PrometheusOperatorRuleOf("Checkout_API", service, []PrometheusRuleGroup{{Name: "group"}})
+
+	// then
+	if diff := deep.Equal(manifest.Metadata, KubernetesObjectMetadata{
+		Name:      "openslo-checkout-api-44f5d9ec",
+		Namespace: "shop",
+		Labels: map[string]string{
+			"prometheus":                "k8s",
+			PROMETHEUS_LABEL_SERVICE:    "Checkout_API",
+			KUBERNETES_LABEL_MANAGED_BY: KUBERNETES_MANAGED_BY_OPENSLO,
+		},
+		Annotations: map[string]string{"owner": "payments"},
+	}); diff != nil {
+		t.Error(diff)
+	}
+}
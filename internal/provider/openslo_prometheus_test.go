@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const prometheusYamlSpec = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: prometheus
+spec:
+  type: prometheus
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+spec:
+  description: Checkout service
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        metricSourceRef: prometheus
+        spec:
+          query: http_requests_total{code=~"5.."}
+    total:
+      metricSource:
+        metricSourceRef: prometheus
+        spec:
+          query: sum(rate(http_requests_total[{{.window}}]))
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+  labels:
+    team: payments
+spec:
+  service: checkout
+  indicatorRef: errors
+  timeWindow:
+  - duration: 30d
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Available
+    target: 0.999
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-latency
+spec:
+  service: checkout
+  indicator:
+    apiVersion: openslo/v1
+    kind: SLI
+    metadata:
+      name: latency
+    spec:
+      thresholdMetric:
+        metricSource:
+          type: prometheus
+          spec:
+            query: histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket[5m])) by (le))
+  budgetingMethod: Timeslices
+  objectives:
+  - displayName: Fast
+    target: 0.95
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: datadog-availability
+spec:
+  indicator:
+    apiVersion: openslo/v1
+    kind: SLI
+    metadata:
+      name: datadog
+    spec:
+      ratioMetric:
+        counter: true
+        good:
+          metricSource:
+            type: datadog
+            spec:
+              query: sum:requests.ok{*}.as_count()
+        total:
+          metricSource:
+            type: datadog
+            spec:
+              query: sum:requests{*}.as_count()
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+`
+
+func TestOpenSLOPrometheusRules_shouldbeValid_ratioMetric(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(prometheusYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	var rules PrometheusRuleFile
+	err = yaml.Unmarshal([]byte(openslo.Prometheus_rules["checkout-availability"]), &rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.Groups) != 3 {
+		t.Fatalf("Expected 3 rule groups, but got %d", len(rules.Groups))
+	}
+
+	// and every window needed by the alerts is recorded, plus the SLO period
+	var records []string
+	for _, rule := range rules.Groups[0].Rules {
+		records = append(records, rule.Record)
+	}
+	diff := deep.Equal(records, []string{
+		"slo:sli_error:ratio_rate5m",
+		"slo:sli_error:ratio_rate30m",
+		"slo:sli_error:ratio_rate1h",
+		"slo:sli_error:ratio_rate2h",
+		"slo:sli_error:ratio_rate6h",
+		"slo:sli_error:ratio_rate1d",
+		"slo:sli_error:ratio_rate3d",
+		"slo:sli_error:ratio_rate30d",
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and selectors are aggregated, queries with the window placeholder are used as is
+	expected := PrometheusRule{
+		Record: "slo:sli_error:ratio_rate1h",
+		Expr:   "(sum(rate(http_requests_total{code=~\"5..\"}[1h])))\n/\n(sum(rate(http_requests_total[1h])))",
+		Labels: map[string]string{
+			"openslo_slo":       "checkout-availability",
+			"openslo_objective": "Available",
+			"openslo_service":   "checkout",
+			"openslo_window":    "1h",
+			"team":              "payments",
+		},
+	}
+	diff = deep.Equal(rules.Groups[0].Rules[2], expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and alerts use the error budget
+	alerts := rules.Groups[2].Rules
+	if len(alerts) != 2 || alerts[0].Labels["severity"] != "page" || alerts[1].Labels["severity"] != "ticket" {
+		t.Fatalf("Expected a page and a ticket alert, but got %+v", alerts)
+	}
+	if !strings.Contains(alerts[0].Expr, `slo:sli_error:ratio_rate1h{openslo_slo="checkout-availability", openslo_objective="Available"} > 0.0144`) {
+		t.Errorf("Expected a 14.4 burn rate on 1h, but got %s", alerts[0].Expr)
+	}
+	if !strings.Contains(alerts[1].Expr, `slo:sli_error:ratio_rate3d{openslo_slo="checkout-availability", openslo_objective="Available"} > 0.001`) {
+		t.Errorf("Expected a 1 burn rate on 3d, but got %s", alerts[1].Expr)
+	}
+
+	// and only prometheus SLOs are rendered
+	if _, ok := openslo.Prometheus_rules["datadog-availability"]; ok {
+		t.Error("Expected datadog SLO to be skipped")
+	}
+}
+
+func TestOpenSLOPrometheusRules_shouldbeWarning_thresholdWithoutOp(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(prometheusYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	if _, ok := openslo.Prometheus_rules["checkout-latency"]; ok {
+		t.Error("Expected threshold SLO without op to be skipped")
+	}
+
+	// and
+	warnings := warningsWithSummary(diagnostics, "Cannot render prometheus rules, skipping")
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, but got %d", len(warnings))
+	}
+	if !strings.Contains(warnings[0].Detail(), "checkout-latency/Fast") {
+		t.Errorf("Expected warning to name the objective, but got %s", warnings[0].Detail())
+	}
+}
+
+func TestOpenSLOPrometheusRules_shouldbeValid_thresholdMetric(t *testing.T) {
+	// given
+	sli := SLIModel{
+		ThresholdMetric: MetricModel{
+			MetricSource: MetricSource{
+				Type: "prometheus",
+				Spec: FreeformMap{"query": "histogram_quantile(0.99, sum(rate(latency_bucket[5m])) by (le))"},
+			},
+		},
+	}
+	objective := ObjectiveModel{Op: "lte", Value: 0.25, Target: 0.99}
+
+	// when
+	expr, err := PrometheusErrorRatio(sli, objective, "1h")
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	expected := "1 - avg_over_time(((histogram_quantile(0.99, sum(rate(latency_bucket[5m])) by (le))) <= bool 0.25)[1h:])"
+	if expr != expected {
+		t.Errorf("Expected %s, but got %s", expected, expr)
+	}
+}
+
+func warningsWithSummary(diagnostics diag.Diagnostics, summary string) diag.Diagnostics {
+	var warnings diag.Diagnostics
+	for _, warning := range diagnostics.Warnings() {
+		if warning.Summary() == summary {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+func TestOpenSLOPrometheusRules_shouldbeValid_labelNames(t *testing.T) {
+	// given
+	slo := SLOModel{
+		ServiceRef: "checkout",
+		Metadata: MetadataModel{
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "checkout",
+				"team":                   "payments",
+				"2fa":                    "required",
+			},
+		},
+	}
+
+	// when
+	labels := PrometheusObjectiveLabels("checkout-availability", slo, "checkout-availability/Available")
+
+	// then
+	diff := deep.Equal(labels, map[string]string{
+		"app_kubernetes_io_name": "checkout",
+		"team":                   "payments",
+		"_2fa":                   "required",
+		"openslo_slo":            "checkout-availability",
+		"openslo_objective":      "Available",
+		"openslo_service":        "checkout",
+	})
+	if diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	PYRRA_API_VERSION      = "pyrra.dev/v1alpha1"
+	PYRRA_KIND             = "ServiceLevelObjective"
+	KUBERNETES_NAME_LENGTH = 63
+)
+
+// Pyrra indicators are series selectors, not PromQL expressions
+var prometheusSeriesSelectorRegex = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)?(\{[^{}]*\})?$`)
+
+var kubernetesNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+type PyrraServiceLevelObjective struct {
+	ApiVersion string                `yaml:"apiVersion"`
+	Kind       string                `yaml:"kind"`
+	Metadata   PyrraMetadata         `yaml:"metadata"`
+	Spec       PyrraServiceLevelSpec `yaml:"spec"`
+}
+
+type PyrraMetadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type PyrraServiceLevelSpec struct {
+	Description string         `yaml:"description,omitempty"`
+	Target      string         `yaml:"target"`
+	Window      string         `yaml:"window"`
+	Indicator   PyrraIndicator `yaml:"indicator"`
+}
+
+type PyrraIndicator struct {
+	Ratio     *PyrraRatioIndicator     `yaml:"ratio,omitempty"`
+	Latency   *PyrraLatencyIndicator   `yaml:"latency,omitempty"`
+	BoolGauge *PyrraBoolGaugeIndicator `yaml:"bool_gauge,omitempty"`
+}
+
+type PyrraRatioIndicator struct {
+	Errors   PyrraMetric `yaml:"errors"`
+	Total    PyrraMetric `yaml:"total"`
+	Grouping []string    `yaml:"grouping,omitempty"`
+}
+
+type PyrraLatencyIndicator struct {
+	Success  PyrraMetric `yaml:"success"`
+	Total    PyrraMetric `yaml:"total"`
+	Grouping []string    `yaml:"grouping,omitempty"`
+}
+
+type PyrraBoolGaugeIndicator struct {
+	PyrraMetric `yaml:",inline"`
+}
+
+type PyrraMetric struct {
+	Metric string `yaml:"metric"`
+}
+
+// RenderPyrraManifests renders the Pyrra ServiceLevelObjective of every prometheus backed objective, keyed by objective
+func (d *OpenSloDataSource) RenderPyrraManifests(diagnostics *diag.Diagnostics) error {
+	d.Pyrra_manifests = map[string]string{}
+	for _, sloName := range sortedKeys(d.Slos) {
+		slo := d.Slos[sloName]
+		for i, key := range ObjectiveKeys(sloName, slo) {
+			objective := slo.Objectives[i]
+			indicator := ObjectiveIndicator(slo, objective)
+			if !IsPrometheusIndicator(indicator) {
+				continue
+			}
+
+			pyrraSlo, err := PyrraServiceLevelObjectiveOf(sloName, key, slo, objective, indicator)
+			if err != nil {
+				diagnostics.AddWarning("Cannot render pyrra manifest, skipping", fmt.Sprintf("objective %s: %s", key, err.Error()))
+				continue
+			}
+			manifest, err := yaml.Marshal(pyrraSlo)
+			if err != nil {
+				return fmt.Errorf("objective %s: %w", key, err)
+			}
+			d.Pyrra_manifests[key] = string(manifest)
+		}
+	}
+	return nil
+}
+
+// PyrraServiceLevelObjectiveOf returns the Pyrra ServiceLevelObjective of an objective. The indicator is a ratio of
+// bad and total events, or a latency when good events are read from a histogram _bucket series.
+func PyrraServiceLevelObjectiveOf(sloName string, key string, slo SLOModel, objective ObjectiveModel, indicator SLIModel) (*PyrraServiceLevelObjective, error) {
+	target := ObjectiveTarget(objective)
+	if target <= 0 || target >= 1 {
+		return nil, fmt.Errorf("target must be between 0 and 1, got %v", target)
+	}
+	if len(slo.TimeWindow) > 0 && !slo.TimeWindow[0].IsRolling {
+		return nil, fmt.Errorf("calendar time windows are not supported by pyrra")
+	}
+	period, err := ParseOpenSloDuration(SloTimeWindow(slo))
+	if err != nil {
+		return nil, fmt.Errorf("timeWindow: %w", err)
+	}
+	pyrraIndicator, err := PyrraIndicatorOf(indicator)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := mergeLabels(slo.Metadata.Labels, map[string]string{
+		PROMETHEUS_LABEL_SLO: sloName,
+	})
+	if slo.ServiceRef != "" {
+		labels[PROMETHEUS_LABEL_SERVICE] = slo.ServiceRef
+	}
+	return &PyrraServiceLevelObjective{
+		ApiVersion: PYRRA_API_VERSION,
+		Kind:       PYRRA_KIND,
+		Metadata: PyrraMetadata{
+			Name:      KubernetesName(key),
+			Namespace: slo.Metadata.Namespace,
+			Labels:    labels,
+		},
+		Spec: PyrraServiceLevelSpec{
+			Description: slo.Description,
+			Target:      formatPrometheusFloat(target * 100),
+			Window:      FormatPrometheusDuration(period),
+			Indicator:   *pyrraIndicator,
+		},
+	}, nil
+}
+
+// PyrraIndicatorOf maps a ratio SLI to a Pyrra indicator. Grouping labels are read from the total
+// metric source spec.grouping, as a list or a comma separated string. Synthetic SLIs read the probe_success
+// series of their monitor as a bool gauge.
+func PyrraIndicatorOf(sli SLIModel) (*PyrraIndicator, error) {
+	if sli.SyntheticMonitorRef != "" {
+		return &PyrraIndicator{BoolGauge: &PyrraBoolGaugeIndicator{PyrraMetric{Metric: SyntheticProbeSelector(sli.SyntheticMonitorRef)}}}, nil
+	}
+	if isMetricSourceSet(sli.ThresholdMetric.MetricSource) {
+		return nil, fmt.Errorf("thresholdMetric is not supported by pyrra, use a ratioMetric of histogram buckets")
+	}
+	ratio := sli.RatioMetric
+	if ratio.RawType != "" || isMetricSourceSet(ratio.Raw.MetricSource) {
+		return nil, fmt.Errorf("ratioMetric.raw is not supported by pyrra")
+	}
+	if !isMetricSourceSet(ratio.Total.MetricSource) {
+		return nil, fmt.Errorf("ratioMetric.total is required by pyrra")
+	}
+	total, err := pyrraMetric(ratio.Total.MetricSource)
+	if err != nil {
+		return nil, fmt.Errorf("ratioMetric.total: %w", err)
+	}
+	grouping := ratio.Total.MetricSource.Spec.StringList("grouping")
+
+	if isMetricSourceSet(ratio.Bad.MetricSource) {
+		errors, err := pyrraMetric(ratio.Bad.MetricSource)
+		if err != nil {
+			return nil, fmt.Errorf("ratioMetric.bad: %w", err)
+		}
+		return &PyrraIndicator{Ratio: &PyrraRatioIndicator{Errors: errors, Total: total, Grouping: grouping}}, nil
+	}
+	if isMetricSourceSet(ratio.Good.MetricSource) {
+		success, err := pyrraMetric(ratio.Good.MetricSource)
+		if err != nil {
+			return nil, fmt.Errorf("ratioMetric.good: %w", err)
+		}
+		if !strings.HasSuffix(prometheusMetricName(success.Metric), "_bucket") {
+			return nil, fmt.Errorf("ratioMetric.good is only supported by pyrra as a histogram _bucket series, use ratioMetric.bad instead")
+		}
+		return &PyrraIndicator{Latency: &PyrraLatencyIndicator{Success: success, Total: total, Grouping: grouping}}, nil
+	}
+	return nil, fmt.Errorf("ratioMetric needs a bad or a good metric")
+}
+
+func pyrraMetric(metricSource MetricSource) (PyrraMetric, error) {
+	query := strings.TrimSpace(metricSource.Spec.Scalar("query"))
+	if query == "" {
+		return PyrraMetric{}, fmt.Errorf("metricSource.spec.query is required")
+	}
+	if !prometheusSeriesSelectorRegex.MatchString(query) {
+		return PyrraMetric{}, fmt.Errorf("query must be a series selector for pyrra, got %q", query)
+	}
+	return PyrraMetric{Metric: query}, nil
+}
+
+func prometheusMetricName(selector string) string {
+	return strings.SplitN(selector, "{", 2)[0]
+}
+
+// KubernetesName converts a key to a valid kubernetes object name (lowercase alphanumerics and dashes). A name that
+// had to be changed or truncated ends with a hash of the key, so that different keys do not overwrite each other.
+func KubernetesName(key string) string {
+	name := strings.Trim(kubernetesNameInvalidChars.ReplaceAllString(strings.ToLower(key), "-"), "-")
+	if name == key && len(name) <= KUBERNETES_NAME_LENGTH {
+		return name
+	}
+	return strings.TrimLeft(truncateWithHash(name, key, KUBERNETES_NAME_LENGTH), "-")
+}
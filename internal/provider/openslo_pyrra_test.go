@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const pyrraYamlSpec = `
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: errors
+spec:
+  ratioMetric:
+    counter: true
+    bad:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total{job="checkout", code=~"5.."}
+    total:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_requests_total{job="checkout"}
+          grouping:
+          - route
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: latency
+spec:
+  ratioMetric:
+    counter: true
+    good:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_request_duration_seconds_bucket{job="checkout", le="0.5"}
+    total:
+      metricSource:
+        type: prometheus
+        spec:
+          query: http_request_duration_seconds_count{job="checkout"}
+          grouping: route, method
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout
+  namespace: payments
+  labels:
+    team: payments
+spec:
+  description: Checkout requests succeed fast
+  indicatorRef: errors
+  timeWindow:
+  - duration: 4w
+    isRolling: true
+  budgetingMethod: Occurrences
+  objectives:
+  - displayName: Available
+    target: 0.999
+  - displayName: Fast
+    targetPercent: 95
+    indicatorRef: latency
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: quantile
+spec:
+  indicator:
+    apiVersion: openslo/v1
+    kind: SLI
+    metadata:
+      name: quantile
+    spec:
+      thresholdMetric:
+        metricSource:
+          type: prometheus
+          spec:
+            query: histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket[5m])) by (le))
+  budgetingMethod: Timeslices
+  objectives:
+  - op: lt
+    value: 0.5
+    target: 0.99
+`
+
+func TestOpenSLOPyrraManifests_shouldbeValid(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(pyrraYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Pyrra_manifests), []string{"checkout/Available", "checkout/Fast"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	var manifest PyrraServiceLevelObjective
+	err = yaml.Unmarshal([]byte(openslo.Pyrra_manifests["checkout/Available"]), &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := PyrraServiceLevelObjective{
+		ApiVersion: "pyrra.dev/v1alpha1",
+		Kind:       "ServiceLevelObjective",
+		Metadata: PyrraMetadata{
+			Name:      "checkout-available-0b283c27",
+			Namespace: "payments",
+			Labels: map[string]string{
+				"team":        "payments",
+				"openslo_slo": "checkout",
+			},
+		},
+		Spec: PyrraServiceLevelSpec{
+			Description: "Checkout requests succeed fast",
+			Target:      "99.9",
+			Window:      "28d",
+			Indicator: PyrraIndicator{
+				Ratio: &PyrraRatioIndicator{
+					Errors:   PyrraMetric{Metric: `http_requests_total{job="checkout", code=~"5.."}`},
+					Total:    PyrraMetric{Metric: `http_requests_total{job="checkout"}`},
+					Grouping: []string{"route"},
+				},
+			},
+		},
+	}
+	diff = deep.Equal(manifest, expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and good histogram buckets are a latency indicator
+	manifest = PyrraServiceLevelObjective{}
+	err = yaml.Unmarshal([]byte(openslo.Pyrra_manifests["checkout/Fast"]), &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedLatency := &PyrraLatencyIndicator{
+		Success:  PyrraMetric{Metric: `http_request_duration_seconds_bucket{job="checkout", le="0.5"}`},
+		Total:    PyrraMetric{Metric: `http_request_duration_seconds_count{job="checkout"}`},
+		Grouping: []string{"route", "method"},
+	}
+	diff = deep.Equal(manifest.Spec.Indicator.Latency, expectedLatency)
+	if diff != nil {
+		t.Error(diff)
+	}
+	if manifest.Spec.Target != "95" {
+		t.Errorf("Expected target 95, but got %s", manifest.Spec.Target)
+	}
+}
+
+func TestOpenSLOPyrraManifests_shouldbeWarning_thresholdMetric(t *testing.T) {
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(pyrraYamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	warnings := warningsWithSummary(diagnostics, "Cannot render pyrra manifest, skipping")
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "quantile/0: thresholdMetric is not supported") {
+		t.Errorf("Expected a warning naming the objective, but got %v", warnings)
+	}
+}
+
+func TestOpenSLOPyrraManifests_shouldbeError_notASeriesSelector(t *testing.T) {
+	// given
+	metricSource := MetricSource{Type: "prometheus", Spec: FreeformMap{"query": "sum(rate(http_requests_total[5m]))"}}
+
+	// when
+	_, err := pyrraMetric(metricSource)
+
+	// then
+	if err == nil {
+		t.Error("Expected error for a PromQL expression, but got nil")
+	}
+}
+
+func TestOpenSLOPyrraManifests_shouldbeUnique_kubernetesNames(t *testing.T) {
+	// when
+	names := []string{
+		KubernetesName("checkout/Available"),
+		KubernetesName("checkout/available"),
+		KubernetesName("checkout-available"),
+		KubernetesName("checkout/" + strings.Repeat("a", 70) + "-eu"),
+		KubernetesName("checkout/" + strings.Repeat("a", 70) + "-us"),
+	}
+
+	// then
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] || len(name) > KUBERNETES_NAME_LENGTH || kubernetesNameInvalidChars.MatchString(name) {
+			t.Errorf("Expected unique valid names, got %v", names)
+		}
+		seen[name] = true
+	}
+	if names[2] != "checkout-available" {
+		t.Errorf("Expected valid names to be kept, got %s", names[2])
+	}
+}
@@ -1,7 +1,10 @@
 package provider
 
 import (
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -22,11 +25,15 @@ var MetadataSchema = types.ObjectType{
 var DataSourceSchema = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
 		"description": types.StringType,
+		// Nested values are JSON-encoded, see FreeformMap
 		"connection_details": types.MapType{
 			ElemType: types.StringType,
 		},
 		"metadata": MetadataSchema,
 		"type":     types.StringType,
+		"used_by_slis": types.ListType{
+			ElemType: types.StringType,
+		},
 	},
 }
 
@@ -34,6 +41,9 @@ var ServiceSchema = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
 		"description": types.StringType,
 		"metadata":    MetadataSchema,
+		"slos": types.ListType{
+			ElemType: types.StringType,
+		},
 	},
 }
 
@@ -69,6 +79,7 @@ var AlertNotificationTargetSchema = types.ObjectType{
 var MetricSourceSchema = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
 		"type": types.StringType,
+		// Nested values are JSON-encoded, see FreeformMap
 		"spec": types.MapType{
 			ElemType: types.StringType,
 		},
@@ -96,10 +107,14 @@ var RatioMetricSchema = types.ObjectType{
 
 var SLISchema = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
-		"description":      types.StringType,
-		"threshold_metric": MetricSchema,
-		"ratio_metric":     RatioMetricSchema,
-		"metadata":         MetadataSchema,
+		"description":           types.StringType,
+		"threshold_metric":      MetricSchema,
+		"ratio_metric":          RatioMetricSchema,
+		"synthetic_monitor_ref": types.StringType,
+		"metadata":              MetadataSchema,
+		"used_by_slos": types.ListType{
+			ElemType: types.StringType,
+		},
 	},
 }
 
@@ -170,3 +185,158 @@ var SLOSchema = types.ObjectType{
 		"metadata": MetadataSchema,
 	},
 }
+
+var FlatObjectiveSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"slo":         types.StringType,
+		"index":       types.Int64Type,
+		"metadata":    MetadataSchema,
+		"description": types.StringType,
+		"objective":   ObjectiveSchema,
+		"service":     ServiceSchema,
+		"service_ref": types.StringType,
+		"indicator":   SLISchema,
+		"time_window": types.ListType{
+			ElemType: TimeWindowSchema,
+		},
+		"budgeting_method": types.StringType,
+		"alert_policies": types.ListType{
+			ElemType: AlertPolicySchema,
+		},
+	},
+}
+
+var BurnRateAlertSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"slo":                  types.StringType,
+		"objective":            types.StringType,
+		"alert_policy":         types.StringType,
+		"alert_condition":      types.StringType,
+		"severity":             types.StringType,
+		"op":                   types.StringType,
+		"burn_rate":            types.NumberType,
+		"target":               types.NumberType,
+		"error_budget":         types.NumberType,
+		"error_rate_threshold": types.NumberType,
+		"time_window":          types.StringType,
+		"long_window":          types.StringType,
+		"short_window":         types.StringType,
+		"alert_after":          types.StringType,
+		"budget_consumed":      types.NumberType,
+		"expression":           types.StringType,
+	},
+}
+
+var DatadogSloSchema = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":        types.StringType,
+		"type":        types.StringType,
+		"description": types.StringType,
+		"query": types.ObjectType{
+			AttrTypes: map[string]attr.Type{
+				"numerator":   types.StringType,
+				"denominator": types.StringType,
+			},
+		},
+		"thresholds": types.ListType{
+			ElemType: types.ObjectType{
+				AttrTypes: map[string]attr.Type{
+					"timeframe": types.StringType,
+					"target":    types.NumberType,
+				},
+			},
+		},
+		"tags": types.ListType{
+			ElemType: types.StringType,
+		},
+	},
+}
+
+// SLIConnectionDetailsPaths are the paths of the connection details of the datasources embedded in an SLI
+var SLIConnectionDetailsPaths = []string{
+	"threshold_metric.metric_source.datasource.connection_details",
+	"ratio_metric.good.metric_source.datasource.connection_details",
+	"ratio_metric.bad.metric_source.datasource.connection_details",
+	"ratio_metric.total.metric_source.datasource.connection_details",
+	"ratio_metric.raw.metric_source.datasource.connection_details",
+}
+
+// prefixedPaths returns the attribute paths under the given attribute
+func prefixedPaths(prefix string, paths ...string) []string {
+	prefixed := make([]string, len(paths))
+	for i, path := range paths {
+		prefixed[i] = prefix + "." + path
+	}
+	return prefixed
+}
+
+// ComputedAttributes converts an object type to computed schema attributes, marking the given dot
+// separated attribute paths as sensitive. Terraform can only hide whole attributes, so the objects
+// leading to a sensitive attribute are converted to nested attributes.
+func ComputedAttributes(objectType types.ObjectType, sensitivePaths ...string) map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for name, attrType := range objectType.AttrTypes {
+		sensitive := false
+		var nestedPaths []string
+		for _, sensitivePath := range sensitivePaths {
+			if sensitivePath == name {
+				sensitive = true
+			} else if strings.HasPrefix(sensitivePath, name+".") {
+				nestedPaths = append(nestedPaths, strings.TrimPrefix(sensitivePath, name+"."))
+			}
+		}
+		if len(nestedPaths) > 0 {
+			attributes[name] = computedNestedAttribute(attrType, nestedPaths)
+		} else {
+			attributes[name] = computedAttribute(attrType, sensitive)
+		}
+	}
+	return attributes
+}
+
+func computedNestedAttribute(attrType attr.Type, sensitivePaths []string) schema.Attribute {
+	switch t := attrType.(type) {
+	case types.ObjectType:
+		return schema.SingleNestedAttribute{
+			Computed:   true,
+			Attributes: ComputedAttributes(t, sensitivePaths...),
+		}
+	case types.ListType:
+		return schema.ListNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: ComputedAttributes(t.ElemType.(types.ObjectType), sensitivePaths...),
+			},
+		}
+	case types.MapType:
+		return schema.MapNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: ComputedAttributes(t.ElemType.(types.ObjectType), sensitivePaths...),
+			},
+		}
+	}
+	panic("cannot nest attributes in a " + attrType.String())
+}
+
+func computedAttribute(attrType attr.Type, sensitive bool) schema.Attribute {
+	switch t := attrType.(type) {
+	case types.ObjectType:
+		return schema.ObjectAttribute{Computed: true, Sensitive: sensitive, AttributeTypes: t.AttrTypes}
+	case types.ListType:
+		return schema.ListAttribute{Computed: true, Sensitive: sensitive, ElementType: t.ElemType}
+	case types.MapType:
+		return schema.MapAttribute{Computed: true, Sensitive: sensitive, ElementType: t.ElemType}
+	}
+	switch attrType {
+	case types.StringType:
+		return schema.StringAttribute{Computed: true, Sensitive: sensitive}
+	case types.BoolType:
+		return schema.BoolAttribute{Computed: true, Sensitive: sensitive}
+	case types.NumberType:
+		return schema.NumberAttribute{Computed: true, Sensitive: sensitive}
+	case types.Int64Type:
+		return schema.Int64Attribute{Computed: true, Sensitive: sensitive}
+	}
+	panic("unsupported attribute type " + attrType.String())
+}
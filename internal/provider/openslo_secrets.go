@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const SECRET_REF_ENV = "env:"
+const SECRET_REF_FILE = "file:"
+
+// resolveSecretReference returns the value pointed by an env:VAR or file:/path reference,
+// any other value is returned as is.
+func resolveSecretReference(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SECRET_REF_ENV):
+		name := strings.TrimPrefix(value, SECRET_REF_ENV)
+		resolved, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("bad secret reference: environment variable %s is not set", name)
+		}
+		return resolved, nil
+	case strings.HasPrefix(value, SECRET_REF_FILE):
+		filePath := strings.TrimPrefix(value, SECRET_REF_FILE)
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("bad secret reference: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	return value, nil
+}
+
+// resolveFreeformSecrets resolves the secret references of every string in the map, nested ones included
+func resolveFreeformSecrets(values FreeformMap) (FreeformMap, error) {
+	if values == nil {
+		return nil, nil
+	}
+	resolved, err := resolveFreeformValueSecrets(map[string]interface{}(values))
+	if err != nil {
+		return nil, err
+	}
+	return FreeformMap(resolved.(map[string]interface{})), nil
+}
+
+func resolveFreeformValueSecrets(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return resolveSecretReference(v)
+	case map[string]interface{}:
+		resolved := map[string]interface{}{}
+		for key, item := range v {
+			resolvedItem, err := resolveFreeformValueSecrets(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			resolved[key] = resolvedItem
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			resolvedItem, err := resolveFreeformValueSecrets(item)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			resolved[i] = resolvedItem
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+// ResolveSecretReferences resolves the connection details of the datasources, and of the copies embedded in the
+// metric sources of the SLIs and SLOs. It runs after the selector, so only the secrets of the kept objects must exist.
+func (d *OpenSloDataSource) ResolveSecretReferences() error {
+	resolved := map[string]FreeformMap{}
+	resolve := func(datasource *DataSourceModel) error {
+		name := datasource.Metadata.Name
+		if name == "" {
+			return nil
+		}
+		if _, ok := resolved[name]; !ok {
+			connectionDetails, err := resolveFreeformSecrets(datasource.ConnectionDetails)
+			if err != nil {
+				return fmt.Errorf("datasource %s connectionDetails: %w", name, err)
+			}
+			resolved[name] = connectionDetails
+		}
+		datasource.ConnectionDetails = resolved[name]
+		return nil
+	}
+	resolveSli := func(sli *SLIModel) error {
+		for _, metricSource := range sli.metricSources() {
+			if err := resolve(&metricSource.DataSource); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for k := range d.Datasources {
+		datasource := d.Datasources[k]
+		if err := resolve(&datasource); err != nil {
+			return err
+		}
+		d.Datasources[k] = datasource
+	}
+	for k := range d.Slis {
+		sli := d.Slis[k]
+		if err := resolveSli(&sli); err != nil {
+			return err
+		}
+		d.Slis[k] = sli
+	}
+	for k := range d.Slos {
+		slo := d.Slos[k]
+		if err := resolveSli(&slo.Indicator); err != nil {
+			return err
+		}
+		for i := range slo.Objectives {
+			if err := resolveSli(&slo.Objectives[i].Indicator); err != nil {
+				return err
+			}
+		}
+		d.Slos[k] = slo
+	}
+	return nil
+}
+
+// ResolveAuthSecretReferences resolves the secret references of the HTTP monitor request auth. Only the synthetics
+// check needs the secrets, the rendered probes read them from the references.
+func (d *OpenSloDataSource) ResolveAuthSecretReferences() error {
+	for name, monitor := range d.Extension_httpmonitor {
+		for i, request := range monitor.Requests {
+			auth, err := resolveAuth(request.Auth)
+			if err != nil {
+				return fmt.Errorf("http monitor %s requests[%d]: auth: %w", name, i, err)
+			}
+			monitor.Requests[i].Auth = auth
+		}
+	}
+	return nil
+}
+
+// metricSources returns the metric sources of the SLI
+func (m *SLIModel) metricSources() []*MetricSource {
+	return []*MetricSource{
+		&m.ThresholdMetric.MetricSource,
+		&m.RatioMetric.Good.MetricSource,
+		&m.RatioMetric.Bad.MetricSource,
+		&m.RatioMetric.Total.MetricSource,
+		&m.RatioMetric.Raw.MetricSource,
+	}
+}
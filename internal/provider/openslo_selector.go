@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	SELECTOR_OP_IN             = "In"
+	SELECTOR_OP_NOT_IN         = "NotIn"
+	SELECTOR_OP_EXISTS         = "Exists"
+	SELECTOR_OP_DOES_NOT_EXIST = "DoesNotExist"
+)
+
+// SelectorModel restricts the computed maps to the objects matching every criteria.
+// References are always resolved against the full input, before the selection happens.
+type SelectorModel struct {
+	Kinds            []string                  `tfsdk:"kinds"`
+	NameRegex        types.String              `tfsdk:"name_regex"`
+	MatchLabels      map[string]string         `tfsdk:"match_labels"`
+	MatchExpressions []SelectorExpressionModel `tfsdk:"match_expressions"`
+}
+
+type SelectorExpressionModel struct {
+	Key      string   `tfsdk:"key"`
+	Operator string   `tfsdk:"operator"`
+	Values   []string `tfsdk:"values"`
+}
+
+var SelectorSchema = schema.SingleNestedAttribute{
+	MarkdownDescription: "Only keep the objects matching all the given criteria in the computed maps. References are still resolved against the full input.",
+	Optional:            true,
+	Attributes: map[string]schema.Attribute{
+		"kinds": schema.ListAttribute{
+			MarkdownDescription: "OpenSLO kinds to keep (e.g. `SLO`, `SLI`, `HTTPMonitor`). All kinds are kept when unset.",
+			Optional:            true,
+			ElementType:         types.StringType,
+		},
+		"name_regex": schema.StringAttribute{
+			MarkdownDescription: "Regular expression the object name must match",
+			Optional:            true,
+		},
+		"match_labels": schema.MapAttribute{
+			MarkdownDescription: "Labels the object must have with the exact same value",
+			Optional:            true,
+			ElementType:         types.StringType,
+		},
+		"match_expressions": schema.ListNestedAttribute{
+			MarkdownDescription: "Label expressions the object must match, with the same semantics as kubernetes label selectors",
+			Optional:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						MarkdownDescription: "Label key",
+						Required:            true,
+					},
+					"operator": schema.StringAttribute{
+						MarkdownDescription: "One of `In`, `NotIn`, `Exists` or `DoesNotExist`",
+						Required:            true,
+					},
+					"values": schema.ListAttribute{
+						MarkdownDescription: "Label values, used by the `In` and `NotIn` operators",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+				},
+			},
+		},
+	},
+}
+
+// Every kind that ends up in a computed map, selectors reject anything else
+var selectableKinds = []string{
+	"DataSource",
+	"Service",
+	"AlertCondition",
+	"AlertNotificationTarget",
+	"AlertPolicy",
+	"SLI",
+	"SLO",
+	"HTTPMonitor",
+	"BrowserMonitor",
+	"TCPMonitor",
+	"DNSMonitor",
+	"GRPCMonitor",
+	"TLSMonitor",
+}
+
+type compiledSelector struct {
+	kinds       map[string]bool
+	nameRegex   *regexp.Regexp
+	matchLabels map[string]string
+	expressions []SelectorExpressionModel
+}
+
+func (s *SelectorModel) compile() (*compiledSelector, error) {
+	compiled := compiledSelector{
+		matchLabels: s.MatchLabels,
+		expressions: s.MatchExpressions,
+	}
+
+	if len(s.Kinds) > 0 {
+		compiled.kinds = map[string]bool{}
+		for _, kind := range s.Kinds {
+			if !containsString(selectableKinds, kind) {
+				return nil, fmt.Errorf("bad selector: unknown kind %s, expected one of %v", kind, selectableKinds)
+			}
+			compiled.kinds[kind] = true
+		}
+	}
+
+	if s.NameRegex.ValueString() != "" {
+		nameRegex, err := regexp.Compile(s.NameRegex.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("bad selector: invalid name_regex: %w", err)
+		}
+		compiled.nameRegex = nameRegex
+	}
+
+	for _, expression := range s.MatchExpressions {
+		switch expression.Operator {
+		case SELECTOR_OP_IN, SELECTOR_OP_NOT_IN:
+			if len(expression.Values) == 0 {
+				return nil, fmt.Errorf("bad selector: operator %s on label %s requires values", expression.Operator, expression.Key)
+			}
+		case SELECTOR_OP_EXISTS, SELECTOR_OP_DOES_NOT_EXIST:
+			if len(expression.Values) != 0 {
+				return nil, fmt.Errorf("bad selector: operator %s on label %s does not accept values", expression.Operator, expression.Key)
+			}
+		default:
+			return nil, fmt.Errorf("bad selector: unknown operator %s on label %s", expression.Operator, expression.Key)
+		}
+	}
+
+	return &compiled, nil
+}
+
+func (s *compiledSelector) matches(kind string, metadata MetadataModel) bool {
+	if s.kinds != nil && !s.kinds[kind] {
+		return false
+	}
+	if s.nameRegex != nil && !s.nameRegex.MatchString(metadata.Name) {
+		return false
+	}
+	for key, value := range s.matchLabels {
+		if labelValue, ok := metadata.Labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	for _, expression := range s.expressions {
+		labelValue, ok := metadata.Labels[expression.Key]
+		switch expression.Operator {
+		case SELECTOR_OP_IN:
+			if !ok || !containsString(expression.Values, labelValue) {
+				return false
+			}
+		case SELECTOR_OP_NOT_IN:
+			if ok && containsString(expression.Values, labelValue) {
+				return false
+			}
+		case SELECTOR_OP_EXISTS:
+			if !ok {
+				return false
+			}
+		case SELECTOR_OP_DOES_NOT_EXIST:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func selectObjects[T any](objects map[string]T, kind string, selector *compiledSelector, metadata func(T) MetadataModel) map[string]T {
+	selected := map[string]T{}
+	for name, object := range objects {
+		if selector.matches(kind, metadata(object)) {
+			selected[name] = object
+		}
+	}
+	return selected
+}
+
+// ApplySelector drops the objects not matching the selector from the computed maps.
+// It must run after the post extraction logic, so references are resolved against the full input.
+func (d *OpenSloDataSource) ApplySelector() error {
+	if d.Selector == nil {
+		return nil
+	}
+
+	selector, err := d.Selector.compile()
+	if err != nil {
+		return err
+	}
+
+	d.Datasources = selectObjects(d.Datasources, "DataSource", selector, func(o DataSourceModel) MetadataModel { return o.Metadata })
+	d.Services = selectObjects(d.Services, "Service", selector, func(o ServiceModel) MetadataModel { return o.Metadata })
+	d.Alert_conditions = selectObjects(d.Alert_conditions, "AlertCondition", selector, func(o AlertConditionModel) MetadataModel { return o.Metadata })
+	d.Alert_notification_targets = selectObjects(d.Alert_notification_targets, "AlertNotificationTarget", selector, func(o AlertNotificationTargetModel) MetadataModel { return o.Metadata })
+	d.Alert_policies = selectObjects(d.Alert_policies, "AlertPolicy", selector, func(o AlertPolicyModel) MetadataModel { return o.Metadata })
+	d.Slis = selectObjects(d.Slis, "SLI", selector, func(o SLIModel) MetadataModel { return o.Metadata })
+	d.Slos = selectObjects(d.Slos, "SLO", selector, func(o SLOModel) MetadataModel { return o.Metadata })
+	d.Extension_httpmonitor = selectObjects(d.Extension_httpmonitor, "HTTPMonitor", selector, func(o HTTPMonitorModel) MetadataModel { return o.Metadata })
+	d.Extension_browsermonitor = selectObjects(d.Extension_browsermonitor, "BrowserMonitor", selector, func(o BrowserMonitorModel) MetadataModel { return o.Metadata })
+	d.Extension_tcpmonitor = selectObjects(d.Extension_tcpmonitor, "TCPMonitor", selector, func(o TCPMonitorModel) MetadataModel { return o.Metadata })
+	d.Extension_dnsmonitor = selectObjects(d.Extension_dnsmonitor, "DNSMonitor", selector, func(o DNSMonitorModel) MetadataModel { return o.Metadata })
+	d.Extension_grpcmonitor = selectObjects(d.Extension_grpcmonitor, "GRPCMonitor", selector, func(o GRPCMonitorModel) MetadataModel { return o.Metadata })
+	d.Extension_tlsmonitor = selectObjects(d.Extension_tlsmonitor, "TLSMonitor", selector, func(o TLSMonitorModel) MetadataModel { return o.Metadata })
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
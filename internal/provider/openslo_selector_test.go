@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const selectorYamlSpec = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: prometheus
+spec:
+  type: prometheus
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+  labels:
+    tier: "1"
+spec:
+  description: Checkout service
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: checkout-availability
+spec:
+  ratioMetric:
+    counter: true
+    good:
+      metricSource:
+        metricSourceRef: prometheus
+        spec:
+          query: http_requests_total{code!~"5.."}
+    total:
+      metricSource:
+        metricSourceRef: prometheus
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+  labels:
+    tier: "1"
+    team: payments
+spec:
+  service: checkout
+  indicatorRef: checkout-availability
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.999
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-latency
+  labels:
+    tier: "2"
+    team: payments
+spec:
+  service: checkout
+  indicatorRef: checkout-availability
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: search-availability
+  labels:
+    team: search
+spec:
+  indicatorRef: checkout-availability
+  budgetingMethod: Occurrences
+  objectives:
+  - target: 0.99
+`
+
+func TestOpenSLOSelector_shouldKeepAll_withoutSelector(t *testing.T) {
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(selectorYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Slos), []string{"checkout-availability", "checkout-latency", "search-availability"})
+	if diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestOpenSLOSelector_shouldFilter_byLabels(t *testing.T) {
+	// given
+	selector := SelectorModel{
+		MatchLabels: map[string]string{
+			"team": "payments",
+		},
+		MatchExpressions: []SelectorExpressionModel{
+			{
+				Key:      "tier",
+				Operator: SELECTOR_OP_IN,
+				Values:   []string{"1"},
+			},
+		},
+	}
+
+	// when
+	openslo := OpenSloDataSource{Selector: &selector}
+	err := openslo.GetOpenSloData(selectorYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Slos), []string{"checkout-availability"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and references are resolved against the full input
+	slo := openslo.Slos["checkout-availability"]
+	if slo.Indicator.Metadata.Name != "checkout-availability" {
+		t.Errorf("Expected indicator to be resolved, but got %s", slo.Indicator.Metadata.Name)
+	}
+	if slo.Indicator.RatioMetric.Good.MetricSource.DataSource.Metadata.Name != "prometheus" {
+		t.Errorf("Expected datasource to be resolved, but got %s", slo.Indicator.RatioMetric.Good.MetricSource.DataSource.Metadata.Name)
+	}
+	if len(openslo.Slis) != 0 || len(openslo.Datasources) != 0 {
+		t.Errorf("Expected unlabeled objects to be filtered out, but got %d slis and %d datasources", len(openslo.Slis), len(openslo.Datasources))
+	}
+}
+
+func TestOpenSLOSelector_shouldFilter_byKindAndName(t *testing.T) {
+	// given
+	selector := SelectorModel{
+		Kinds:     []string{"SLO", "Service"},
+		NameRegex: types.StringValue("^checkout"),
+		MatchExpressions: []SelectorExpressionModel{
+			{
+				Key:      "tier",
+				Operator: SELECTOR_OP_NOT_IN,
+				Values:   []string{"1"},
+			},
+		},
+	}
+
+	// when
+	openslo := OpenSloDataSource{Selector: &selector}
+	err := openslo.GetOpenSloData(selectorYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Slos), []string{"checkout-latency"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	if len(openslo.Services) != 0 || len(openslo.Slis) != 0 || len(openslo.Datasources) != 0 {
+		t.Errorf("Expected only SLOs, but got %d services, %d slis and %d datasources", len(openslo.Services), len(openslo.Slis), len(openslo.Datasources))
+	}
+}
+
+func TestOpenSLOSelector_shouldFilter_byLabelExistence(t *testing.T) {
+	// given
+	selector := SelectorModel{
+		Kinds: []string{"SLO"},
+		MatchExpressions: []SelectorExpressionModel{
+			{
+				Key:      "tier",
+				Operator: SELECTOR_OP_DOES_NOT_EXIST,
+			},
+		},
+	}
+
+	// when
+	openslo := OpenSloDataSource{Selector: &selector}
+	err := openslo.GetOpenSloData(selectorYamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(sortedKeys(openslo.Slos), []string{"search-availability"})
+	if diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestOpenSLOSelector_shouldbeError_badSelector(t *testing.T) {
+	// given
+	selectors := []SelectorModel{
+		{Kinds: []string{"Unknown"}},
+		{NameRegex: types.StringValue("(")},
+		{MatchExpressions: []SelectorExpressionModel{{Key: "tier", Operator: "Equals"}}},
+		{MatchExpressions: []SelectorExpressionModel{{Key: "tier", Operator: SELECTOR_OP_IN}}},
+	}
+
+	for _, selector := range selectors {
+		// when
+		diagnostics := diag.Diagnostics{}
+		selector := selector
+		openslo := OpenSloDataSource{Selector: &selector}
+		err := openslo.GetOpenSloData(selectorYamlSpec, &diagnostics)
+
+		// then
+		if err == nil {
+			t.Errorf("Expected error for selector %+v, but got nil", selector)
+			continue
+		}
+
+		// and
+		if !strings.Contains(diagnostics.Errors()[0].Summary(), "Selector Error") {
+			t.Errorf("Expected 'Selector Error', but got %s", diagnostics.Errors()[0].Summary())
+		}
+	}
+}
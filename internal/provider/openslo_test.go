@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestOpenSLODatasource_shouldbeValid_singleYamlSpec(t *testing.T) {
@@ -33,7 +40,7 @@ func TestOpenSLODatasource_shouldbeValid_singleYamlSpec(t *testing.T) {
 		},
 		Type:        "datasource-type",
 		Description: "Datasource description",
-		ConnectionDetails: map[string]string{
+		ConnectionDetails: map[string]interface{}{
 			"host":     "my-host",
 			"port":     "my-port",
 			"user":     "my-user",
@@ -54,6 +61,164 @@ func TestOpenSLODatasource_shouldbeValid_singleYamlSpec(t *testing.T) {
 	}
 }
 
+func TestOpenSLODatasource_shouldResolve_secretReferences(t *testing.T) {
+	// given
+	secretFile := filepath.Join(t.TempDir(), "password")
+	err := os.WriteFile(secretFile, []byte("my-file-password\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OPENSLO_TEST_API_KEY", "my-env-api-key")
+
+	yamlSpec := `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: my-datasource
+spec:
+  type: datadog
+  connectionDetails:
+    site: datadoghq.eu
+    apiKey: env:OPENSLO_TEST_API_KEY
+    auth:
+      password: file:` + secretFile + `
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: my-sli
+spec:
+  thresholdMetric:
+    metricSource:
+      metricSourceRef: my-datasource
+`
+
+	expected := map[string]interface{}{
+		"site":   "datadoghq.eu",
+		"apiKey": "my-env-api-key",
+		"auth": map[string]interface{}{
+			"password": "my-file-password",
+		},
+	}
+
+	// when
+	openslo := OpenSloDataSource{}
+	err = openslo.GetOpenSloData(yamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(map[string]interface{}(openslo.Datasources["my-datasource"].ConnectionDetails), expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and the datasource embedded in metric sources is resolved too
+	embedded := openslo.Slis["my-sli"].ThresholdMetric.MetricSource.DataSource
+	diff = deep.Equal(map[string]interface{}(embedded.ConnectionDetails), expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	schemaResp := datasource.SchemaResponse{}
+	openslo.Schema(context.Background(), datasource.SchemaRequest{}, &schemaResp)
+	connectionDetails, diags := schemaResp.Schema.AttributeAtPath(context.Background(), path.Root("datasources").AtMapKey("my-datasource").AtName("connection_details"))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !connectionDetails.IsSensitive() {
+		t.Error("Expected connection_details to be sensitive")
+	}
+	for _, attributePath := range []path.Path{
+		path.Root("slis").AtMapKey("my-sli").AtName("threshold_metric").AtName("metric_source").AtName("datasource").AtName("connection_details"),
+		path.Root("slos").AtMapKey("my-slo").AtName("objectives").AtListIndex(0).AtName("indicator").AtName("ratio_metric").AtName("total").AtName("metric_source").AtName("datasource").AtName("connection_details"),
+		path.Root("objectives").AtMapKey("my-slo/0").AtName("indicator").AtName("ratio_metric").AtName("good").AtName("metric_source").AtName("datasource").AtName("connection_details"),
+	} {
+		embeddedConnectionDetails, diags := schemaResp.Schema.AttributeAtPath(context.Background(), attributePath)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if !embeddedConnectionDetails.IsSensitive() {
+			t.Errorf("Expected %s to be sensitive", attributePath)
+		}
+	}
+}
+
+func TestOpenSLODatasource_shouldbeError_badSecretReference(t *testing.T) {
+	// given
+	yamlSpec := `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: my-datasource
+spec:
+  type: datadog
+  connectionDetails:
+    apiKey: env:OPENSLO_TEST_UNDEFINED_VARIABLE
+`
+
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(yamlSpec, &diagnostics)
+
+	// then
+	if err == nil {
+		t.Fatal("Expected error, but got nil")
+	}
+
+	// and
+	if !strings.Contains(err.Error(), "OPENSLO_TEST_UNDEFINED_VARIABLE") {
+		t.Errorf("Expected error to name the variable, but got %s", err.Error())
+	}
+
+	// and
+	if !strings.Contains(diagnostics.Errors()[0].Summary(), "OpenSLO Secret Reference Error") {
+		t.Errorf("Expected 'OpenSLO Secret Reference Error', but got %s", diagnostics.Errors()[0].Summary())
+	}
+}
+
+func TestOpenSLODatasource_shouldIgnore_badSecretReferenceOfUnselectedDatasource(t *testing.T) {
+	// given
+	yamlSpec := `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: my-datasource
+spec:
+  type: datadog
+  connectionDetails:
+    apiKey: env:OPENSLO_TEST_UNDEFINED_VARIABLE
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: my-service
+spec:
+  description: My service
+`
+	selector := SelectorModel{Kinds: []string{"Service"}}
+
+	// when
+	diagnostics := diag.Diagnostics{}
+	openslo := OpenSloDataSource{Selector: &selector}
+	err := openslo.GetOpenSloData(yamlSpec, &diagnostics)
+
+	// then
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and
+	if len(openslo.Datasources) != 0 || len(openslo.Services) != 1 {
+		t.Errorf("Expected only the service, but got datasources %v and services %v", openslo.Datasources, openslo.Services)
+	}
+}
+
 func TestOpenSLOService_shouldbeValid_singleYamlSpec(t *testing.T) {
 	// given
 	yamlSpec := `
@@ -194,6 +359,99 @@ spec:
 	}
 }
 
+func TestOpenSLOSLI_shouldbeValid_nestedSpecState(t *testing.T) {
+	// given
+	yamlSpec := `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: cloudwatch
+spec:
+  type: cloudwatch
+  connectionDetails:
+    region: eu-west-1
+    roleArn: arn:aws:iam::123456789012:role/slo
+    retries: 3
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: latency
+spec:
+  thresholdMetric:
+    metricSource:
+      metricSourceRef: cloudwatch
+      spec:
+        region: eu-west-1
+        stat: p99
+        metricStat:
+          period: 60
+          metric:
+            namespace: AWS/ApplicationELB
+            metricName: TargetResponseTime
+            dimensions:
+            - name: LoadBalancer
+              value: app/my-lb
+        queries:
+        - sum(rate(a[5m]))
+        - sum(rate(b[5m]))
+`
+
+	expected := map[string]string{
+		"region":     "eu-west-1",
+		"stat":       "p99",
+		"metricStat": `{"metric":{"dimensions":[{"name":"LoadBalancer","value":"app/my-lb"}],"metricName":"TargetResponseTime","namespace":"AWS/ApplicationELB"},"period":60}`,
+		"queries":    `["sum(rate(a[5m]))","sum(rate(b[5m]))"]`,
+	}
+
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(yamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	state := openSloState(t, &openslo)
+	var spec map[string]string
+	diags := state.GetAttribute(context.Background(), path.Root("slis").AtMapKey("latency").AtName("threshold_metric").AtName("metric_source").AtName("spec"), &spec)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	diff := deep.Equal(spec, expected)
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	var connectionDetails map[string]string
+	diags = state.GetAttribute(context.Background(), path.Root("datasources").AtMapKey("cloudwatch").AtName("connection_details"), &connectionDetails)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if connectionDetails["retries"] != "3" {
+		t.Errorf("Expected retries to be 3, but got %s", connectionDetails["retries"])
+	}
+}
+
+// openSloState stores the data source in a terraform state, like the Read method does
+func openSloState(t *testing.T, openslo *OpenSloDataSource) tfsdk.State {
+	ctx := context.Background()
+	schemaResp := datasource.SchemaResponse{}
+	openslo.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := state.Set(ctx, openslo)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	return state
+}
+
 func TestOpenSLOAlertPolicy_shouldbeValid_singleYamlSpec(t *testing.T) {
 	// given
 	yamlSpec := `
@@ -631,7 +889,8 @@ spec:
 			Name:        "default",
 			DisplayName: "Default",
 		},
-		Type: "datadog",
+		Type:       "datadog",
+		UsedBySlis: []string{"default-success-rate"},
 	}
 
 	service := ServiceModel{
@@ -640,6 +899,7 @@ spec:
 			DisplayName: "My Service",
 		},
 		Description: "This service does blablabla",
+		Slos:        []string{"string"},
 	}
 
 	alertCondition := AlertConditionModel{
@@ -691,6 +951,7 @@ spec:
 			DisplayName: "string",
 		},
 		Description: "string",
+		UsedBySlos:  []string{"string"},
 		RatioMetric: RatioMetricModel{
 			Counter: true,
 			Good: MetricModel{
@@ -794,6 +1055,116 @@ spec:
 
 }
 
+func TestOpenSLOAll_shouldbeValid_backReferences(t *testing.T) {
+	// given
+	yamlSpec := `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: prometheus
+spec:
+  type: prometheus
+---
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: unused
+spec:
+  type: prometheus
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: my-service
+spec:
+  description: This service does blablabla
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: availability
+spec:
+  ratioMetric:
+    good:
+      metricSource:
+        metricSourceRef: prometheus
+    total:
+      metricSource:
+        metricSourceRef: prometheus
+---
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: latency
+spec:
+  thresholdMetric:
+    metricSource:
+      metricSourceRef: prometheus
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: slo-b
+spec:
+  service: my-service
+  indicatorRef: availability
+  objectives:
+  - target: 0.99
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: slo-a
+spec:
+  service: my-service
+  objectives:
+  - target: 0.99
+    indicatorRef: availability
+  - target: 0.95
+    indicatorRef: latency
+`
+
+	// when
+	openslo := OpenSloDataSource{}
+	err := openslo.GetOpenSloData(yamlSpec, &diag.Diagnostics{})
+
+	// then
+	if err != nil {
+		t.Error(err)
+	}
+
+	// and
+	diff := deep.Equal(openslo.Services["my-service"].Slos, []string{"slo-a", "slo-b"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	diff = deep.Equal(openslo.Slis["availability"].UsedBySlos, []string{"slo-a", "slo-b"})
+	if diff != nil {
+		t.Error(diff)
+	}
+	diff = deep.Equal(openslo.Slis["latency"].UsedBySlos, []string{"slo-a"})
+	if diff != nil {
+		t.Error(diff)
+	}
+
+	// and
+	diff = deep.Equal(openslo.Datasources["prometheus"].UsedBySlis, []string{"availability", "latency"})
+	if diff != nil {
+		t.Error(diff)
+	}
+	if openslo.Datasources["unused"].UsedBySlis != nil {
+		t.Errorf("Expected no back references, but got %v", openslo.Datasources["unused"].UsedBySlis)
+	}
+
+	// and embedded copies have back references too
+	diff = deep.Equal(openslo.Slos["slo-b"].Service.Slos, []string{"slo-a", "slo-b"})
+	if diff != nil {
+		t.Error(diff)
+	}
+}
+
 func TestOpenSLO_shouldbeWarning_badApiVersion(t *testing.T) {
 	// given
 	yamlSpec := `
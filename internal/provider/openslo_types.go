@@ -22,15 +22,17 @@ type MetadataModel struct {
 }
 
 type DataSourceModel struct {
-	Type              string            `tfsdk:"type" yaml:"type"`
-	ConnectionDetails map[string]string `tfsdk:"connection_details" yaml:"connectionDetails"`
-	Metadata          MetadataModel     `tfsdk:"metadata" yaml:"metadata"`
-	Description       string            `tfsdk:"description" yaml:"description"`
+	Type              string        `tfsdk:"type" yaml:"type"`
+	ConnectionDetails FreeformMap   `tfsdk:"connection_details" yaml:"connectionDetails"`
+	Metadata          MetadataModel `tfsdk:"metadata" yaml:"metadata"`
+	Description       string        `tfsdk:"description" yaml:"description"`
+	UsedBySlis        []string      `tfsdk:"used_by_slis" yaml:"-"`
 }
 
 type ServiceModel struct {
 	Description string        `tfsdk:"description" yaml:"description"`
 	Metadata    MetadataModel `tfsdk:"metadata" yaml:"metadata"`
+	Slos        []string      `tfsdk:"slos" yaml:"-"`
 }
 
 type AlertConditionModelCondition struct {
@@ -83,10 +85,12 @@ type AlertPolicyModelWrapper struct {
 }
 
 type SLIModel struct {
-	Description     string           `tfsdk:"description" yaml:"description"`
-	ThresholdMetric MetricModel      `tfsdk:"threshold_metric" yaml:"thresholdMetric,omitempty"`
-	RatioMetric     RatioMetricModel `tfsdk:"ratio_metric" yaml:"ratioMetric,omitempty"`
-	Metadata        MetadataModel    `tfsdk:"metadata" yaml:"metadata"`
+	Description         string           `tfsdk:"description" yaml:"description"`
+	ThresholdMetric     MetricModel      `tfsdk:"threshold_metric" yaml:"thresholdMetric,omitempty"`
+	RatioMetric         RatioMetricModel `tfsdk:"ratio_metric" yaml:"ratioMetric,omitempty"`
+	SyntheticMonitorRef string           `tfsdk:"synthetic_monitor_ref" yaml:"syntheticMonitorRef"`
+	Metadata            MetadataModel    `tfsdk:"metadata" yaml:"metadata"`
+	UsedBySlos          []string         `tfsdk:"used_by_slos" yaml:"-"`
 }
 
 type MetricModel struct {
@@ -94,10 +98,10 @@ type MetricModel struct {
 }
 
 type MetricSource struct {
-	MetricSourceRef string                 `tfsdk:"metric_source_ref" yaml:"metricSourceRef"`
-	DataSource      DataSourceModel        `tfsdk:"datasource" yaml:"-"`
-	Type            string                 `tfsdk:"type" yaml:"type"`
-	Spec            map[string]interface{} `tfsdk:"spec" yaml:"spec"`
+	MetricSourceRef string          `tfsdk:"metric_source_ref" yaml:"metricSourceRef"`
+	DataSource      DataSourceModel `tfsdk:"datasource" yaml:"-"`
+	Type            string          `tfsdk:"type" yaml:"type"`
+	Spec            FreeformMap     `tfsdk:"spec" yaml:"spec"`
 }
 
 type RatioMetricModel struct {
@@ -148,3 +152,58 @@ type ObjectiveModel struct {
 	IndicatorInternal YamlSpecTyped[SLIModel] `tfsdk:"-" yaml:"indicator,omitempty"`
 	CompositeWeight   float64                 `tfsdk:"composite_weight" yaml:"compositeWeight"`
 }
+
+// FlatObjectiveModel is a single objective of an SLO, with everything resolved from the SLO
+type FlatObjectiveModel struct {
+	Slo             string             `tfsdk:"slo"`
+	Index           int64              `tfsdk:"index"`
+	Metadata        MetadataModel      `tfsdk:"metadata"`
+	Description     string             `tfsdk:"description"`
+	Objective       ObjectiveModel     `tfsdk:"objective"`
+	Service         ServiceModel       `tfsdk:"service"`
+	ServiceRef      string             `tfsdk:"service_ref"`
+	Indicator       SLIModel           `tfsdk:"indicator"`
+	TimeWindow      []TimeWindowModel  `tfsdk:"time_window"`
+	BudgetingMethod string             `tfsdk:"budgeting_method"`
+	AlertPolicies   []AlertPolicyModel `tfsdk:"alert_policies"`
+}
+
+// BurnRateAlertModel is a multi-window multi-burn-rate alert of an objective, derived from a burnrate alert condition
+type BurnRateAlertModel struct {
+	Slo                string  `tfsdk:"slo"`
+	Objective          string  `tfsdk:"objective"`
+	AlertPolicy        string  `tfsdk:"alert_policy"`
+	AlertCondition     string  `tfsdk:"alert_condition"`
+	Severity           string  `tfsdk:"severity"`
+	Op                 string  `tfsdk:"op"`
+	BurnRate           float64 `tfsdk:"burn_rate"`
+	Target             float64 `tfsdk:"target"`
+	ErrorBudget        float64 `tfsdk:"error_budget"`
+	ErrorRateThreshold float64 `tfsdk:"error_rate_threshold"`
+	TimeWindow         string  `tfsdk:"time_window"`
+	LongWindow         string  `tfsdk:"long_window"`
+	ShortWindow        string  `tfsdk:"short_window"`
+	AlertAfter         string  `tfsdk:"alert_after"`
+	BudgetConsumed     float64 `tfsdk:"budget_consumed"`
+	Expression         string  `tfsdk:"expression"`
+}
+
+// DatadogSloModel is a metric based Datadog SLO, matching the datadog_service_level_objective resource
+type DatadogSloModel struct {
+	Name        string                     `tfsdk:"name"`
+	Type        string                     `tfsdk:"type"`
+	Description string                     `tfsdk:"description"`
+	Query       DatadogSloQueryModel       `tfsdk:"query"`
+	Thresholds  []DatadogSloThresholdModel `tfsdk:"thresholds"`
+	Tags        []string                   `tfsdk:"tags"`
+}
+
+type DatadogSloQueryModel struct {
+	Numerator   string `tfsdk:"numerator"`
+	Denominator string `tfsdk:"denominator"`
+}
+
+type DatadogSloThresholdModel struct {
+	Timeframe string  `tfsdk:"timeframe"`
+	Target    float64 `tfsdk:"target"`
+}
@@ -45,6 +45,7 @@ func (p *OpenSloProvider) Resources(ctx context.Context) []func() resource.Resou
 func (p *OpenSloProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewOpenSloDataSource,
+		NewSyntheticsCheckDataSource,
 	}
 }
 